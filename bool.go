@@ -16,10 +16,218 @@ package ursa
 // You should have received a copy of the GNU General Public License
 // along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
 type boolValidatorOpt = parseOpt[bool]
 
+// DefaultTruthy and DefaultFalsy are the strings accepted as booleans, case is ignored
+var (
+	DefaultTruthy = []string{"1", "t", "true", "y", "yes", "on"}
+	DefaultFalsy  = []string{"0", "f", "false", "n", "no", "off"}
+)
+
+type boolConfig struct {
+	truthy   []string
+	falsy    []string
+	checkbox bool
+}
+
+type boolOpt func(c *boolConfig)
+
+// WithTruthy replaces the strings accepted as true e.g. WithTruthy(append(DefaultTruthy, "ja")...)
+func WithTruthy(values ...string) boolOpt {
+	return func(c *boolConfig) {
+		c.truthy = values
+	}
+}
+
+// WithFalsy replaces the strings accepted as false
+func WithFalsy(values ...string) boolOpt {
+	return func(c *boolConfig) {
+		c.falsy = values
+	}
+}
+
+// Checkbox treats a missing or empty value as false, browsers don't send unchecked checkboxes
+func Checkbox() boolOpt {
+	return func(c *boolConfig) {
+		c.checkbox = true
+	}
+}
+
 func Bool(opts ...any) genericValidator[bool] {
-	return validatorFactory[bool](opts...)
+	return boolValidatorFactory(opts...)
+}
+
+func boolValidatorFactory(opts ...any) validatorWithOpts[bool] {
+	cfg, remaining := newBoolConfig(opts)
+	if cfg.checkbox {
+		remaining = append([]any{WithDefault(false)}, remaining...)
+	}
+	v := validatorFactory[bool](remaining...)
+	if !v.hasTransformer() {
+		v.setTransformer(func(val any) (any, error) {
+			b, ok, err := cfg.coerce(val)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				// only checkboxes reach here as other empty values are rejected
+				return false, nil
+			}
+			return b, nil
+		})
+	}
+	return v
+}
+
+func newBoolConfig(opts []any) (*boolConfig, []any) {
+	cfg := &boolConfig{truthy: DefaultTruthy, falsy: DefaultFalsy}
+	remaining := make([]any, 0, len(opts))
+	for _, opt := range opts {
+		if opt, ok := opt.(boolOpt); ok {
+			opt(cfg)
+			continue
+		}
+		remaining = append(remaining, opt)
+	}
+	return cfg, remaining
+}
+
+// coerce returns false for ok if the value is empty and empty values are allowed
+func (c *boolConfig) coerce(val any) (b bool, ok bool, err error) {
+	vo := reflect.Indirect(reflect.ValueOf(val))
+	if !vo.IsValid() {
+		return false, false, InvalidTypeError
+	}
+
+	var s string
+	switch vo.Kind() {
+	case reflect.Bool:
+		return vo.Bool(), true, nil
+	case reflect.String:
+		s = strings.TrimSpace(vo.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		s = fmt.Sprint(vo.Interface())
+	default:
+		return false, false, InvalidTypeError
+	}
+
+	if s == "" && c.checkbox {
+		return false, false, nil
+	}
+	for _, t := range c.truthy {
+		if strings.EqualFold(s, t) {
+			return true, true, nil
+		}
+	}
+	for _, f := range c.falsy {
+		if strings.EqualFold(s, f) {
+			return false, true, nil
+		}
+	}
+	return false, false, InvalidTypeError
+}
+
+func coerceToBool(val string) (bool, error) {
+	b, _, err := (&boolConfig{truthy: DefaultTruthy, falsy: DefaultFalsy}).coerce(val)
+	return b, err
+}
+
+// OptionalBool is a boolean which may not have been given e.g. a preference left at the default
+type OptionalBool int8
+
+const (
+	BoolUnset OptionalBool = iota
+	BoolFalse
+	BoolTrue
+)
+
+// OptionalBoolOf returns BoolTrue or BoolFalse
+func OptionalBoolOf(b bool) OptionalBool {
+	if b {
+		return BoolTrue
+	}
+	return BoolFalse
+}
+
+// Bool returns the value and whether it was set
+func (b OptionalBool) Bool() (bool, bool) {
+	return b == BoolTrue, b != BoolUnset
+}
+
+// Ptr returns nil if the value is unset
+func (b OptionalBool) Ptr() *bool {
+	if b == BoolUnset {
+		return nil
+	}
+	val := b == BoolTrue
+	return &val
+}
+
+func (b OptionalBool) String() string {
+	switch b {
+	case BoolTrue:
+		return "true"
+	case BoolFalse:
+		return "false"
+	}
+	return "unset"
+}
+
+func (b OptionalBool) MarshalJSON() ([]byte, error) {
+	switch b {
+	case BoolTrue:
+		return []byte("true"), nil
+	case BoolFalse:
+		return []byte("false"), nil
+	}
+	return []byte("null"), nil
+}
+
+func (b *OptionalBool) UnmarshalJSON(data []byte) error {
+	var val *bool
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+	*b = BoolUnset
+	if val != nil {
+		*b = OptionalBoolOf(*val)
+	}
+	return nil
+}
+
+// TriStateBool parses booleans keeping missing and empty values apart from false, the bool options such as
+// WithTruthy are accepted
+func TriStateBool(opts ...any) genericValidator[OptionalBool] {
+	return triStateBoolValidatorFactory(opts...)
+}
+
+func triStateBoolValidatorFactory(opts ...any) validatorWithOpts[OptionalBool] {
+	cfg, remaining := newBoolConfig(opts)
+	// empty strings are unset rather than invalid
+	cfg.checkbox = true
+	v := validatorFactory[OptionalBool](remaining...)
+	if !v.hasTransformer() {
+		v.setTransformer(func(val any) (any, error) {
+			b, ok, err := cfg.coerce(val)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return BoolUnset, nil
+			}
+			return OptionalBoolOf(b), nil
+		})
+	}
+	return v
 }
 
 func True(message ...string) boolValidatorOpt {
@@ -0,0 +1,297 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// CalendarDate is a date without a time or zone e.g. a date of birth or the day of a booking
+type CalendarDate struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+type dateValidatorOpt = parseOpt[CalendarDate]
+
+var InvalidDateError = &parseError{message: "invalid date", code: "invalid_date"}
+
+// DateOf returns the date of t in its own location
+func DateOf(t time.Time) CalendarDate {
+	y, m, d := t.Date()
+	return CalendarDate{Year: y, Month: m, Day: d}
+}
+
+// ParseCalendarDate parses an ISO 8601 date e.g. 2024-02-29
+func ParseCalendarDate(s string) (CalendarDate, error) {
+	t, err := time.Parse(time.DateOnly, strings.TrimSpace(s))
+	if err != nil {
+		return CalendarDate{}, err
+	}
+	return DateOf(t), nil
+}
+
+func (d CalendarDate) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+func (d CalendarDate) IsZero() bool {
+	return d == CalendarDate{}
+}
+
+// In returns midnight at the start of the date in the location
+func (d CalendarDate) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+func (d CalendarDate) Compare(other CalendarDate) int {
+	return d.In(time.UTC).Compare(other.In(time.UTC))
+}
+
+func (d CalendarDate) Before(other CalendarDate) bool {
+	return d.Compare(other) < 0
+}
+
+func (d CalendarDate) After(other CalendarDate) bool {
+	return d.Compare(other) > 0
+}
+
+func (d CalendarDate) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *CalendarDate) UnmarshalText(data []byte) error {
+	parsed, err := ParseCalendarDate(string(data))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Date parses calendar dates from ISO 8601 strings, the date of a time.Time is taken in its own location.
+// The time options e.g. MinAge, InPast, Weekday or BusinessDay are applied to midnight at the start of the date.
+func Date(opts ...any) genericValidator[CalendarDate] {
+	return dateValidatorFactory(opts...)
+}
+
+func dateValidatorFactory(opts ...any) validatorWithOpts[CalendarDate] {
+	converted := make([]any, 0, len(opts))
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case fieldRefinerOpt:
+			// the object's Date builder removes these
+			converted = append(converted, fieldOutsideObjectOpt())
+		case relativeTimeOpt:
+			// e.g. MinAge or InPast, the date starts at midnight where the clock is so today isn't in the future
+			converted = append(converted, clockedOpt[CalendarDate](func(now time.Time, val CalendarDate) *parseError {
+				return opt(now, val.In(now.Location()))
+			}))
+		case timeValidatorOpt:
+			// e.g. Weekday or BusinessDay
+			converted = append(converted, dateValidatorOpt(func(val *CalendarDate) *parseError {
+				if val == nil {
+					return nil
+				}
+				t := val.In(time.UTC)
+				return opt(&t)
+			}))
+		default:
+			converted = append(converted, opt)
+		}
+	}
+	v := validatorFactory[CalendarDate](converted...)
+	if !v.hasTransformer() {
+		v.setTransformer(func(val any) (any, error) {
+			return coerceToDate(val)
+		})
+	}
+	return v
+}
+
+func coerceToDate(val any) (CalendarDate, error) {
+	vo := reflect.Indirect(reflect.ValueOf(val))
+	if !vo.IsValid() {
+		return CalendarDate{}, InvalidTypeError
+	}
+	switch val := vo.Interface().(type) {
+	case time.Time:
+		return DateOf(val), nil
+	case string:
+		d, err := ParseCalendarDate(val)
+		if err != nil {
+			return CalendarDate{}, InvalidDateError
+		}
+		return d, nil
+	}
+	return CalendarDate{}, InvalidTypeError
+}
+
+func NotBeforeDate(datum CalendarDate, message ...string) dateValidatorOpt {
+	return func(val *CalendarDate) *parseError {
+		if val != nil && val.Before(datum) {
+			return timeError("date_too_early", "date is too early", map[string]any{"min": datum.String()}, message...)
+		}
+		return nil
+	}
+}
+
+func NotAfterDate(datum CalendarDate, message ...string) dateValidatorOpt {
+	return func(val *CalendarDate) *parseError {
+		if val != nil && val.After(datum) {
+			return timeError("date_too_late", "date is too late", map[string]any{"max": datum.String()}, message...)
+		}
+		return nil
+	}
+}
+
+// ClockTime is a time of day without a date or zone e.g. an opening time
+type ClockTime struct {
+	Hour   int
+	Minute int
+	Second int
+}
+
+type timeOfDayValidatorOpt = parseOpt[ClockTime]
+
+var InvalidTimeOfDayError = &parseError{message: "invalid time of day", code: "invalid_time_of_day"}
+
+// clockTimeFormats are tried in order, the first is the value of an HTML time input
+var clockTimeFormats = []string{"15:04", "15:04:05", "3:04PM", "3:04pm", "3:04 PM", "3:04 pm"}
+
+func ClockTimeOf(t time.Time) ClockTime {
+	return ClockTime{Hour: t.Hour(), Minute: t.Minute(), Second: t.Second()}
+}
+
+// ParseClockTime accepts 24 hour times with optional seconds e.g. 09:30 or 17:45:10, and 12 hour times e.g. 9:30pm
+func ParseClockTime(s string) (ClockTime, error) {
+	s = strings.TrimSpace(s)
+	var lastErr error
+	for _, layout := range clockTimeFormats {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return ClockTimeOf(t), nil
+		}
+		lastErr = err
+	}
+	return ClockTime{}, lastErr
+}
+
+func (c ClockTime) String() string {
+	if c.Second == 0 {
+		return fmt.Sprintf("%02d:%02d", c.Hour, c.Minute)
+	}
+	return fmt.Sprintf("%02d:%02d:%02d", c.Hour, c.Minute, c.Second)
+}
+
+// SinceMidnight returns the time of day as a duration
+func (c ClockTime) SinceMidnight() time.Duration {
+	return time.Duration(c.Hour)*time.Hour + time.Duration(c.Minute)*time.Minute + time.Duration(c.Second)*time.Second
+}
+
+// On returns the time on the date in the location
+func (c ClockTime) On(date CalendarDate, loc *time.Location) time.Time {
+	return time.Date(date.Year, date.Month, date.Day, c.Hour, c.Minute, c.Second, 0, loc)
+}
+
+func (c ClockTime) Compare(other ClockTime) int {
+	a, b := c.SinceMidnight(), other.SinceMidnight()
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func (c ClockTime) Before(other ClockTime) bool {
+	return c.Compare(other) < 0
+}
+
+func (c ClockTime) After(other ClockTime) bool {
+	return c.Compare(other) > 0
+}
+
+func (c ClockTime) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *ClockTime) UnmarshalText(data []byte) error {
+	parsed, err := ParseClockTime(string(data))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// TimeOfDay parses times of day from strings, the clock time of a time.Time is taken in its own location
+func TimeOfDay(opts ...any) genericValidator[ClockTime] {
+	return timeOfDayValidatorFactory(opts...)
+}
+
+func timeOfDayValidatorFactory(opts ...any) validatorWithOpts[ClockTime] {
+	v := validatorFactory[ClockTime](opts...)
+	if !v.hasTransformer() {
+		v.setTransformer(func(val any) (any, error) {
+			return coerceToClockTime(val)
+		})
+	}
+	return v
+}
+
+func coerceToClockTime(val any) (ClockTime, error) {
+	vo := reflect.Indirect(reflect.ValueOf(val))
+	if !vo.IsValid() {
+		return ClockTime{}, InvalidTypeError
+	}
+	switch val := vo.Interface().(type) {
+	case time.Time:
+		return ClockTimeOf(val), nil
+	case string:
+		c, err := ParseClockTime(val)
+		if err != nil {
+			return ClockTime{}, InvalidTimeOfDayError
+		}
+		return c, nil
+	}
+	return ClockTime{}, InvalidTypeError
+}
+
+// TimeBetween accepts times from start up to and including end, if end is before start the range
+// wraps past midnight e.g. TimeBetween(22:00, 02:00) for a late opening
+func TimeBetween(start, end ClockTime, message ...string) timeOfDayValidatorOpt {
+	return func(val *ClockTime) *parseError {
+		if val == nil {
+			return nil
+		}
+		inside := !val.Before(start) && !val.After(end)
+		if end.Before(start) {
+			inside = !val.Before(start) || !val.After(end)
+		}
+		if !inside {
+			return timeError("time_out_of_range", "time of day is out of range",
+				map[string]any{"start": start.String(), "end": end.String()}, message...)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+// Package cbor registers a CBOR body decoder with ursa, import it for its side effects:
+//
+//	import _ "github.com/jdudmesh/ursa/cbor"
+package cbor
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+	u "github.com/jdudmesh/ursa"
+)
+
+func init() {
+	u.RegisterDecoder("application/cbor", Decode)
+}
+
+// Decode reads a CBOR map, the library supports nesting limits between 4 and 65535 and ursa checks the result
+// against smaller limits
+func Decode(data []byte, maxDepth int) (map[string]interface{}, error) {
+	// nested maps are decoded with string keys so they can be validated by nested object schemas
+	decMode, err := cbor.DecOptions{
+		DefaultMapType:  reflect.TypeOf(map[string]interface{}{}),
+		MaxNestedLevels: min(max(maxDepth, 4), 65535),
+	}.DecMode()
+	if err != nil {
+		return nil, err
+	}
+
+	unpacked := make(map[string]interface{})
+	if err := decMode.Unmarshal(data, &unpacked); err != nil {
+		var nestedErr *cbor.MaxNestedLevelError
+		if errors.As(err, &nestedErr) {
+			return nil, u.ErrDecodeTooDeep
+		}
+		return nil, err
+	}
+	return unpacked, nil
+}
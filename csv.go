@@ -0,0 +1,207 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"iter"
+	"strings"
+	"unicode"
+)
+
+// CSVRowError describes a single problem in a CSV file, Column is empty for errors which apply to the whole row
+type CSVRowError struct {
+	Row     int
+	Column  string
+	Message string
+}
+
+type csvConfig struct {
+	comma   rune
+	aliases map[string]string
+}
+
+type csvOpt func(c *csvConfig)
+
+// CSVComma sets the field delimiter e.g. '\t' for tab separated files
+func CSVComma(comma rune) csvOpt {
+	return func(c *csvConfig) {
+		c.comma = comma
+	}
+}
+
+// CSVAlias maps a column heading onto a schema field
+func CSVAlias(column string, field string) csvOpt {
+	return func(c *csvConfig) {
+		c.aliases[strings.ToLower(strings.TrimSpace(column))] = field
+	}
+}
+
+type CSVValidation struct {
+	reader  io.Reader
+	schema  *objectValidator
+	cfg     *csvConfig
+	errors  []CSVRowError
+	err     error
+	started bool
+}
+
+// ValidateCSV validates each row of a CSV file against the schema. The first row must contain the column headings
+// which are matched to the schema fields by name, ignoring case, or by alias.
+func ValidateCSV(r io.Reader, schema *objectValidator, opts ...csvOpt) *CSVValidation {
+	cfg := &csvConfig{
+		comma:   ',',
+		aliases: make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &CSVValidation{
+		reader: r,
+		schema: schema,
+		cfg:    cfg,
+		errors: make([]CSVRowError, 0),
+	}
+}
+
+// Rows yields the valid rows keyed by row number, the header is row 1. Invalid rows are recorded in Errors.
+// The file can only be read once so Rows should only be iterated once.
+func (c *CSVValidation) Rows() iter.Seq2[int, *objectParseResult] {
+	return func(yield func(int, *objectParseResult) bool) {
+		if c.started {
+			c.err = errors.New("CSV rows have already been read")
+			return
+		}
+		c.started = true
+
+		if err := c.schema.Error(); err != nil {
+			c.err = err
+			return
+		}
+
+		reader := csv.NewReader(c.reader)
+		reader.Comma = c.cfg.comma
+		reader.TrimLeadingSpace = !unicode.IsSpace(c.cfg.comma)
+
+		header, err := reader.Read()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			c.err = err
+			return
+		}
+		columns := c.mapColumns(header)
+		headings := make(map[string]string)
+		for i, field := range columns {
+			if field > "" {
+				headings[field] = strings.TrimSpace(header[i])
+			}
+		}
+
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return
+			}
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				c.errors = append(c.errors, CSVRowError{Row: parseErr.StartLine, Message: parseErr.Err.Error()})
+				continue
+			}
+			if err != nil {
+				c.err = err
+				return
+			}
+			row, _ := reader.FieldPos(0)
+
+			res := c.schema.Parse(c.rowValues(columns, record))
+			if !res.IsAllValid() {
+				c.appendErrors(row, headings, res)
+				continue
+			}
+			if !yield(row, res) {
+				return
+			}
+		}
+	}
+}
+
+func (c *CSVValidation) Errors() []CSVRowError {
+	return c.errors
+}
+
+// Err returns any error which prevented the file from being read
+func (c *CSVValidation) Err() error {
+	return c.err
+}
+
+// mapColumns returns the schema field for each column, unknown columns map to an empty string
+func (c *CSVValidation) mapColumns(header []string) []string {
+	columns := make([]string, len(header))
+	for i, heading := range header {
+		heading = strings.TrimSpace(heading)
+		if field, ok := c.cfg.aliases[strings.ToLower(heading)]; ok {
+			columns[i] = field
+			continue
+		}
+		for _, field := range c.schema.fields {
+			if strings.EqualFold(field, heading) {
+				columns[i] = field
+				break
+			}
+		}
+	}
+	return columns
+}
+
+func (c *CSVValidation) rowValues(columns []string, record []string) map[string]interface{} {
+	values := make(map[string]interface{})
+	for i, field := range columns {
+		// empty cells are treated as missing values so defaults and required checks apply
+		if field == "" || i >= len(record) || record[i] == "" {
+			continue
+		}
+		values[field] = record[i]
+	}
+	return values
+}
+
+func (c *CSVValidation) appendErrors(row int, headings map[string]string, res *objectParseResult) {
+	fieldErrors := make(map[*parseError]bool)
+	for _, field := range c.schema.fields {
+		fieldRes, ok := res.value[field]
+		if !ok {
+			continue
+		}
+		column, ok := headings[field]
+		if !ok {
+			column = field
+		}
+		for _, err := range fieldRes.errors {
+			fieldErrors[err] = true
+			c.errors = append(c.errors, CSVRowError{Row: row, Column: column, Message: err.Error()})
+		}
+	}
+	for _, err := range res.errors {
+		if !fieldErrors[err] {
+			c.errors = append(c.errors, CSVRowError{Row: row, Message: err.Error()})
+		}
+	}
+}
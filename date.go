@@ -17,36 +17,167 @@ package ursa
 // along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
 import (
+	"encoding/json"
+	"math/big"
 	"reflect"
+	"strconv"
+	"strings"
 	"time"
 )
 
-var ErrMissingDateParser = &parseError{message: "missing date parser"}
-
 type timeValidatorOpt = parseOpt[time.Time]
 
+// DatetimeLocal is the layout used by HTML datetime-local inputs, the seconds are optional
+const DatetimeLocal = "2006-01-02T15:04"
+
+// DefaultTimeFormats are tried in order when no formats are given
+var DefaultTimeFormats = []string{time.RFC3339Nano, "2006-01-02T15:04:05", DatetimeLocal, time.DateOnly}
+
+type timeConfig struct {
+	layouts []string
+	unit    time.Duration
+	assume  *time.Location
+	in      *time.Location
+}
+
+type timeOpt func(c *timeConfig)
+
 func Time(opts ...any) genericValidator[time.Time] {
-	return validatorFactory[time.Time](opts...)
+	return timeValidatorFactory(opts...)
 }
 
-func coerceToTime(layout string, val any) (time.Time, error) {
-	vo := reflect.ValueOf(val)
-	if vo.Kind() == reflect.Ptr {
-		vo = vo.Elem()
-		return coerceToTime(layout, vo.Interface())
+func timeValidatorFactory(opts ...any) validatorWithOpts[time.Time] {
+	cfg := &timeConfig{
+		layouts: DefaultTimeFormats,
+		assume:  time.UTC,
 	}
-	if vo.Kind() != reflect.String {
-		return time.Time{}, InvalidValueError
+	for _, opt := range opts {
+		if opt, ok := opt.(timeOpt); ok {
+			opt(cfg)
+		}
+	}
+	remaining := make([]any, 0, len(opts))
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case timeOpt:
+		case fieldRefinerOpt:
+			// the object's Time builder removes these
+			remaining = append(remaining, fieldOutsideObjectOpt())
+		default:
+			remaining = append(remaining, opt)
+		}
 	}
-	return time.Parse(layout, val.(string))
-}
 
-func WithTimeFormat(layout string) genericValidatorOpt {
-	return func(v genericValidatorOptReceiver) error {
+	if cfg.in != nil {
+		// converting first means the other options see the local time
+		remaining = append([]any{timeValidatorOpt(func(val *time.Time) *parseError {
+			if val != nil {
+				*val = val.In(cfg.in)
+			}
+			return nil
+		})}, remaining...)
+	}
+
+	v := validatorFactory[time.Time](remaining...)
+	if !v.hasTransformer() {
 		v.setTransformer(func(val any) (any, error) {
-			return coerceToTime(layout, val)
+			return cfg.coerceToTime(val)
 		})
-		return nil
+	}
+	return v
+}
+
+func (c *timeConfig) coerceToTime(val any) (time.Time, error) {
+	vo := reflect.Indirect(reflect.ValueOf(val))
+	if !vo.IsValid() {
+		return time.Time{}, InvalidTypeError
+	}
+
+	if num, ok := vo.Interface().(json.Number); ok {
+		return c.fromUnix(num.String())
+	}
+
+	switch vo.Kind() {
+	case reflect.String:
+		s := strings.TrimSpace(vo.String())
+		var lastErr error
+		for _, layout := range c.layouts {
+			t, err := time.ParseInLocation(layout, s, c.assume)
+			if err == nil {
+				return t, nil
+			}
+			lastErr = err
+		}
+		if c.unit > 0 {
+			if t, err := c.fromUnix(s); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, &parseError{message: "invalid time", inner: []error{lastErr}, code: "invalid_time"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return c.fromUnix(strconv.FormatInt(vo.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return c.fromUnix(strconv.FormatUint(vo.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		return c.fromUnix(strconv.FormatFloat(vo.Float(), 'f', -1, 64))
+	}
+	return time.Time{}, InvalidTypeError
+}
+
+// fromUnix converts a timestamp exactly, fractions are kept down to the nanosecond
+func (c *timeConfig) fromUnix(s string) (time.Time, error) {
+	if c.unit == 0 {
+		return time.Time{}, InvalidTypeError
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || strings.ContainsAny(s, "/xXoObB") {
+		return time.Time{}, InvalidTypeError
+	}
+	nanos := new(big.Rat).Mul(r, new(big.Rat).SetInt64(int64(c.unit)))
+	n := new(big.Int).Quo(nanos.Num(), nanos.Denom())
+	if !n.IsInt64() {
+		return time.Time{}, NumberOutOfRangeError
+	}
+	return time.Unix(0, n.Int64()).In(c.assume), nil
+}
+
+// WithTimeFormat parses strings using the layout instead of the default formats
+func WithTimeFormat(layout string) timeOpt {
+	return WithTimeFormats(layout)
+}
+
+// WithTimeFormats parses strings using the first of the layouts which matches
+func WithTimeFormats(layouts ...string) timeOpt {
+	return func(c *timeConfig) {
+		c.layouts = layouts
+	}
+}
+
+// UnixSeconds accepts numbers as seconds since the Unix epoch
+func UnixSeconds() timeOpt {
+	return func(c *timeConfig) {
+		c.unit = time.Second
+	}
+}
+
+// UnixMillis accepts numbers as milliseconds since the Unix epoch
+func UnixMillis() timeOpt {
+	return func(c *timeConfig) {
+		c.unit = time.Millisecond
+	}
+}
+
+// AssumeLocation is used for inputs without a zone e.g. datetime-local values, the default is UTC
+func AssumeLocation(loc *time.Location) timeOpt {
+	return func(c *timeConfig) {
+		c.assume = loc
+	}
+}
+
+// InLocation converts the parsed time to the location e.g. to store times in UTC
+func InLocation(loc *time.Location) timeOpt {
+	return func(c *timeConfig) {
+		c.in = loc
 	}
 }
 
@@ -79,3 +210,253 @@ func NotAfter(datum time.Time, message ...string) timeValidatorOpt {
 		return nil
 	}
 }
+
+// Clock supplies the current time to relative constraints such as InPast, inject a fixed clock in tests
+type Clock interface {
+	Now() time.Time
+}
+
+type ClockFunc func() time.Time
+
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+var SystemClock Clock = ClockFunc(time.Now)
+
+// clockOpt replaces the clock used by the clockedOpts of a validator
+type clockOpt struct {
+	clock Clock
+}
+
+// WithClock replaces the system clock used by relative constraints e.g. InPast or UUIDNotInFuture
+func WithClock(clock Clock) clockOpt {
+	return clockOpt{clock: clock}
+}
+
+// clockedOpt is evaluated against the clock each time a value is parsed
+type clockedOpt[T any] func(now time.Time, val T) *parseError
+
+type relativeTimeOpt = clockedOpt[time.Time]
+
+func timeError(code, defaultMessage string, params map[string]any, message ...string) *parseError {
+	if len(message) > 0 {
+		return &parseError{message: message[0], code: code, params: params}
+	}
+	return &parseError{message: defaultMessage, code: code, params: params}
+}
+
+func InPast(message ...string) relativeTimeOpt {
+	return func(now, val time.Time) *parseError {
+		if val.After(now) {
+			return timeError("not_in_past", "date must be in the past", nil, message...)
+		}
+		return nil
+	}
+}
+
+func InFuture(message ...string) relativeTimeOpt {
+	return func(now, val time.Time) *parseError {
+		if !val.After(now) {
+			return timeError("not_in_future", "date must be in the future", nil, message...)
+		}
+		return nil
+	}
+}
+
+// WithinLast accepts times between now and d ago e.g. a receipt from the last 90 days
+func WithinLast(d time.Duration, message ...string) relativeTimeOpt {
+	return func(now, val time.Time) *parseError {
+		if val.Before(now.Add(-d)) || val.After(now) {
+			return timeError("not_within_last", "date is outside the allowed period", map[string]any{"duration": d.String()}, message...)
+		}
+		return nil
+	}
+}
+
+// WithinNext accepts times between now and d from now e.g. a booking window
+func WithinNext(d time.Duration, message ...string) relativeTimeOpt {
+	return func(now, val time.Time) *parseError {
+		if val.Before(now) || val.After(now.Add(d)) {
+			return timeError("not_within_next", "date is outside the allowed period", map[string]any{"duration": d.String()}, message...)
+		}
+		return nil
+	}
+}
+
+// MinAge treats the value as a date of birth and checks the age in whole years,
+// people born on 29 February become a year older on 1 March in other years
+func MinAge(years int, message ...string) relativeTimeOpt {
+	return func(now, val time.Time) *parseError {
+		if age(val, now) < years {
+			return timeError("too_young", "age is too low", map[string]any{"minAge": years}, message...)
+		}
+		return nil
+	}
+}
+
+func MaxAge(years int, message ...string) relativeTimeOpt {
+	return func(now, val time.Time) *parseError {
+		if age(val, now) > years {
+			return timeError("too_old", "age is too high", map[string]any{"maxAge": years}, message...)
+		}
+		return nil
+	}
+}
+
+// age is counted on the calendar of the date of birth so a date parsed as UTC isn't a day out
+func age(birth, now time.Time) int {
+	now = now.In(birth.Location())
+	years := now.Year() - birth.Year()
+	if now.Month() < birth.Month() || (now.Month() == birth.Month() && now.Day() < birth.Day()) {
+		years--
+	}
+	return years
+}
+
+// Weekday accepts times falling on one of the days, the day is taken in the location of the value
+func Weekday(days ...time.Weekday) timeValidatorOpt {
+	names := make([]string, len(days))
+	for i, d := range days {
+		names[i] = d.String()
+	}
+	return func(val *time.Time) *parseError {
+		if val == nil {
+			return nil
+		}
+		for _, d := range days {
+			if val.Weekday() == d {
+				return nil
+			}
+		}
+		return &parseError{message: "day of the week not allowed", code: "invalid_weekday", params: map[string]any{"weekdays": names}}
+	}
+}
+
+// HolidayCalendar reports whether a date is a public holiday
+type HolidayCalendar interface {
+	IsHoliday(date time.Time) bool
+}
+
+type HolidayCalendarFunc func(date time.Time) bool
+
+func (f HolidayCalendarFunc) IsHoliday(date time.Time) bool {
+	return f(date)
+}
+
+// HolidayList is a HolidayCalendar of fixed dates, only the year, month and day are compared
+type HolidayList struct {
+	dates map[string]struct{}
+}
+
+func NewHolidayList(dates ...time.Time) *HolidayList {
+	h := &HolidayList{dates: make(map[string]struct{})}
+	for _, d := range dates {
+		h.dates[d.Format(time.DateOnly)] = struct{}{}
+	}
+	return h
+}
+
+func (h *HolidayList) IsHoliday(date time.Time) bool {
+	_, ok := h.dates[date.Format(time.DateOnly)]
+	return ok
+}
+
+// BusinessDay accepts Monday to Friday unless the calendar has a holiday on the day, the calendar may be nil
+func BusinessDay(calendar HolidayCalendar, message ...string) timeValidatorOpt {
+	return func(val *time.Time) *parseError {
+		if val == nil {
+			return nil
+		}
+		weekend := val.Weekday() == time.Saturday || val.Weekday() == time.Sunday
+		if weekend || (calendar != nil && calendar.IsHoliday(*val)) {
+			return timeError("not_business_day", "date is not a business day", nil, message...)
+		}
+		return nil
+	}
+}
+
+var FieldOptionOutsideObjectError = &parseError{
+	message: "NotBeforeField and NotAfterField can only be used on a field of an object",
+}
+
+// fieldRefinerOpt is passed to a time or date field of an object and compares it with another field of the same
+// type once both have been parsed
+type fieldRefinerOpt struct {
+	other  string
+	refine func(field string) objectRefinerFunc
+}
+
+// NotBeforeField checks the field is at or after another time or date field e.g. Time("end", NotBeforeField("start"))
+func NotBeforeField(other string, message ...string) fieldRefinerOpt {
+	return compareTimeFields(other, func(cmp int) *parseError {
+		if cmp < 0 {
+			return timeError("before_field", "date is too early", map[string]any{"field": other}, message...)
+		}
+		return nil
+	})
+}
+
+// NotAfterField checks the field is at or before another time or date field
+func NotAfterField(other string, message ...string) fieldRefinerOpt {
+	return compareTimeFields(other, func(cmp int) *parseError {
+		if cmp > 0 {
+			return timeError("after_field", "date is too late", map[string]any{"field": other}, message...)
+		}
+		return nil
+	})
+}
+
+// compareTimeFields passes the result of comparing the field with the other field to check, the object checks
+// the fields have the same type
+func compareTimeFields(other string, check func(cmp int) *parseError) fieldRefinerOpt {
+	return fieldRefinerOpt{other: other, refine: func(field string) objectRefinerFunc {
+		return func(res ObjectParseResult) {
+			r, ok := res.(*objectParseResult)
+			if !ok {
+				return
+			}
+			fieldRes, ok := r.value[field]
+			if !ok || !fieldRes.valid {
+				return
+			}
+			otherRes, ok := r.value[other]
+			if !ok || !otherRes.valid {
+				return
+			}
+
+			var cmp int
+			switch val := fieldRes.value.(type) {
+			case time.Time:
+				otherVal, ok := otherRes.value.(time.Time)
+				if !ok {
+					return
+				}
+				cmp = val.Compare(otherVal)
+			case CalendarDate:
+				otherVal, ok := otherRes.value.(CalendarDate)
+				if !ok {
+					return
+				}
+				cmp = val.Compare(otherVal)
+			default:
+				return
+			}
+
+			if err := check(cmp); err != nil {
+				fieldRes.valid = false
+				fieldRes.errors = append(fieldRes.errors, err)
+				r.valid = false
+				r.errors = append(r.errors, err)
+			}
+		}
+	}}
+}
+
+// fieldOutsideObjectOpt replaces a fieldRefinerOpt given to a standalone validator, there are no other fields to
+// compare with
+func fieldOutsideObjectOpt() genericValidatorOpt {
+	return func(v genericValidatorOptReceiver) error {
+		return FieldOptionOutsideObjectError
+	}
+}
@@ -0,0 +1,70 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"math/big"
+)
+
+// Decimal parses arbitrary precision decimals from strings, JSON numbers and Go numbers without passing through float64
+func Decimal(opts ...any) genericValidator[*big.Rat] {
+	return decimalValidatorFactory(opts...)
+}
+
+// BigInt parses arbitrary precision integers, values with a fractional part are rejected
+func BigInt(opts ...any) genericValidator[*big.Int] {
+	return bigIntValidatorFactory(opts...)
+}
+
+func decimalValidatorFactory(opts ...any) validatorWithOpts[*big.Rat] {
+	v := validatorFactory[*big.Rat](wrapNumericOpts[*big.Rat](opts)...)
+	if !v.hasTransformer() {
+		v.setTransformer(func(val any) (any, error) {
+			return coerceToDecimal(val)
+		})
+	}
+	return v
+}
+
+func bigIntValidatorFactory(opts ...any) validatorWithOpts[*big.Int] {
+	v := validatorFactory[*big.Int](wrapNumericOpts[*big.Int](opts)...)
+	if !v.hasTransformer() {
+		v.setTransformer(func(val any) (any, error) {
+			return coerceToBigInt(val)
+		})
+	}
+	return v
+}
+
+func coerceToDecimal(val any) (*big.Rat, error) {
+	r, ok := toRat(val)
+	if !ok {
+		return nil, InvalidValueError
+	}
+	return r, nil
+}
+
+func coerceToBigInt(val any) (*big.Int, error) {
+	r, ok := toRat(val)
+	if !ok {
+		return nil, InvalidValueError
+	}
+	if !r.IsInt() {
+		return nil, InexactNumberError
+	}
+	return new(big.Int).Set(r.Num()), nil
+}
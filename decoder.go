@@ -0,0 +1,184 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+)
+
+const (
+	FormatJSON = "application/json"
+	FormatXML  = "application/xml"
+)
+
+// DefaultMaxDecodeDepth limits the nesting of decoded documents when MaxJSONDepth isn't set
+const DefaultMaxDecodeDepth = 10000
+
+// ErrDecodeTooDeep is returned by decoders when a document is nested more deeply than the limit they are given
+var ErrDecodeTooDeep = errors.New("document is nested too deeply")
+
+var DocumentTooDeepError = &parseError{
+	message: "document is nested too deeply",
+}
+
+// BodyDecoder converts an encoded document into the values which are then validated by an object schema.
+// The root object is at depth 1, decoders must return ErrDecodeTooDeep rather than read past maxDepth
+// so a malicious document can't exhaust the stack.
+type BodyDecoder func(data []byte, maxDepth int) (map[string]interface{}, error)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]BodyDecoder{
+		FormatXML:  decodeXML,
+		"text/xml": decodeXML,
+	}
+)
+
+// RegisterDecoder makes a decoder available for request bodies and ParseFormat, it replaces any existing decoder for the media type
+func RegisterDecoder(mediaType string, dec BodyDecoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[strings.ToLower(mediaType)] = dec
+}
+
+// resolveMediaType maps structured syntax suffixes e.g. application/problem+json onto the base format
+func resolveMediaType(mediaType string) string {
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if mediaType == FormatJSON {
+		return mediaType
+	}
+
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+
+	if _, ok := decoders[mediaType]; ok {
+		return mediaType
+	}
+	if ix := strings.LastIndex(mediaType, "+"); ix >= 0 {
+		base := "application/" + mediaType[ix+1:]
+		if _, ok := decoders[base]; ok || base == FormatJSON {
+			return base
+		}
+	}
+	return mediaType
+}
+
+func lookupDecoder(mediaType string) (BodyDecoder, bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	dec, ok := decoders[mediaType]
+	return dec, ok
+}
+
+// decodeXML treats the children and attributes of the root element as the object properties,
+// repeated elements become lists and elements without children become strings
+func decodeXML(data []byte, maxDepth int) (map[string]interface{}, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil, errors.New("missing root element")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			val, err := decodeXMLElement(dec, start, 1, maxDepth)
+			if err != nil {
+				return nil, err
+			}
+			if m, ok := val.(map[string]interface{}); ok {
+				return m, nil
+			}
+			return make(map[string]interface{}), nil
+		}
+	}
+}
+
+func decodeXMLElement(dec *xml.Decoder, start xml.StartElement, depth, maxDepth int) (any, error) {
+	if depth > maxDepth {
+		return nil, ErrDecodeTooDeep
+	}
+	children := make(map[string]interface{})
+	repeated := make(map[string]bool)
+	for _, attr := range start.Attr {
+		children[attr.Name.Local] = attr.Value
+	}
+
+	text := new(strings.Builder)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			val, err := decodeXMLElement(dec, tok, depth+1, maxDepth)
+			if err != nil {
+				return nil, err
+			}
+			name := tok.Name.Local
+			existing, ok := children[name]
+			switch {
+			case !ok:
+				children[name] = val
+			case repeated[name]:
+				children[name] = append(existing.([]any), val)
+			default:
+				children[name] = []any{existing, val}
+				repeated[name] = true
+			}
+		case xml.CharData:
+			text.Write(tok)
+		case xml.EndElement:
+			if len(children) == 0 {
+				return strings.TrimSpace(text.String()), nil
+			}
+			return children, nil
+		}
+	}
+}
+
+// exceedsDepth checks the result of a decoder in case it doesn't apply the limit itself
+func exceedsDepth(val any, depth, maxDepth int) bool {
+	switch val := val.(type) {
+	case map[string]interface{}:
+		if depth > maxDepth {
+			return true
+		}
+		for _, v := range val {
+			if exceedsDepth(v, depth+1, maxDepth) {
+				return true
+			}
+		}
+	case []any:
+		if depth > maxDepth {
+			return true
+		}
+		for _, v := range val {
+			if exceedsDepth(v, depth+1, maxDepth) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,281 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"math/big"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type durationValidatorOpt = parseOpt[time.Duration]
+
+var InvalidDurationError = &parseError{message: "invalid duration", code: "invalid_duration"}
+
+// AmbiguousDurationError is returned for ISO 8601 durations in years or months which have no fixed length
+var AmbiguousDurationError = &parseError{message: "duration in years or months is ambiguous", code: "ambiguous_duration"}
+
+var isoDurationPattern = regexp.MustCompile(`^([-+])?P(?:(\d+(?:[.,]\d+)?)Y)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)W)?(?:(\d+(?:[.,]\d+)?)D)?` +
+	`(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+
+// isoDurationUnits are the lengths of the weeks, days, hours, minutes and seconds groups, a day is always 24 hours
+var isoDurationUnits = []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+
+// Duration parses Go duration strings e.g. 1h30m and ISO 8601 durations e.g. P1DT2H,
+// numbers are rejected because the unit would be a guess
+func Duration(opts ...any) genericValidator[time.Duration] {
+	return durationValidatorFactory(opts...)
+}
+
+func durationValidatorFactory(opts ...any) validatorWithOpts[time.Duration] {
+	v := validatorFactory[time.Duration](opts...)
+	if !v.hasTransformer() {
+		v.setTransformer(func(val any) (any, error) {
+			return coerceToDuration(val)
+		})
+	}
+	return v
+}
+
+func coerceToDuration(val any) (time.Duration, error) {
+	vo := reflect.Indirect(reflect.ValueOf(val))
+	if !vo.IsValid() || vo.Kind() != reflect.String {
+		return 0, InvalidTypeError
+	}
+	d, err := parseDuration(vo.String())
+	if err != nil {
+		return 0, err
+	}
+	return d, nil
+}
+
+func parseDuration(s string) (time.Duration, *parseError) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	return parseISODuration(s)
+}
+
+func parseISODuration(s string) (time.Duration, *parseError) {
+	m := isoDurationPattern.FindStringSubmatch(strings.ToUpper(s))
+	if m == nil || strings.HasSuffix(m[0], "P") || strings.HasSuffix(m[0], "T") {
+		return 0, InvalidDurationError
+	}
+	if m[2] > "" || m[3] > "" {
+		return 0, AmbiguousDurationError
+	}
+
+	total := new(big.Rat)
+	for i, unit := range isoDurationUnits {
+		part := m[i+4]
+		if part == "" {
+			continue
+		}
+		r, ok := new(big.Rat).SetString(strings.ReplaceAll(part, ",", "."))
+		if !ok {
+			return 0, InvalidDurationError
+		}
+		total.Add(total, r.Mul(r, new(big.Rat).SetInt64(int64(unit))))
+	}
+	if m[1] == "-" {
+		total.Neg(total)
+	}
+
+	n := new(big.Int).Quo(total.Num(), total.Denom())
+	if !n.IsInt64() {
+		return 0, NumberOutOfRangeError
+	}
+	return time.Duration(n.Int64()), nil
+}
+
+func MinDuration(min time.Duration, message ...string) durationValidatorOpt {
+	return func(val *time.Duration) *parseError {
+		if val != nil && *val < min {
+			return timeError("duration_too_short", "duration too short", map[string]any{"min": min.String()}, message...)
+		}
+		return nil
+	}
+}
+
+func MaxDuration(max time.Duration, message ...string) durationValidatorOpt {
+	return func(val *time.Duration) *parseError {
+		if val != nil && *val > max {
+			return timeError("duration_too_long", "duration too long", map[string]any{"max": max.String()}, message...)
+		}
+		return nil
+	}
+}
+
+// TimeInterval is the period from Start up to End e.g. a reporting range
+type TimeInterval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+type intervalValidatorOpt = parseOpt[TimeInterval]
+
+func (i TimeInterval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// Contains reports whether t is in the interval, the end is excluded
+func (i TimeInterval) Contains(t time.Time) bool {
+	return !t.Before(i.Start) && t.Before(i.End)
+}
+
+func (i TimeInterval) String() string {
+	return i.Start.Format(time.RFC3339Nano) + "/" + i.End.Format(time.RFC3339Nano)
+}
+
+// Interval parses objects e.g. {"start": "2024-01-01", "end": "2024-02-01"} and ISO 8601 intervals e.g.
+// "2024-01-01/2024-02-01" or "2024-01-01T09:00:00Z/PT8H". The start and end are parsed like Time and accept
+// the same format and location options. The end may not be before the start.
+func Interval(opts ...any) genericValidator[TimeInterval] {
+	return intervalValidatorFactory(opts...)
+}
+
+func intervalValidatorFactory(opts ...any) validatorWithOpts[TimeInterval] {
+	cfg := &timeConfig{
+		layouts: DefaultTimeFormats,
+		assume:  time.UTC,
+	}
+	remaining := []any{intervalValidatorOpt(checkIntervalOrder)}
+	for _, opt := range opts {
+		if opt, ok := opt.(timeOpt); ok {
+			opt(cfg)
+			continue
+		}
+		remaining = append(remaining, opt)
+	}
+	if cfg.in != nil {
+		remaining = append([]any{intervalValidatorOpt(func(val *TimeInterval) *parseError {
+			if val != nil {
+				val.Start, val.End = val.Start.In(cfg.in), val.End.In(cfg.in)
+			}
+			return nil
+		})}, remaining...)
+	}
+
+	v := validatorFactory[TimeInterval](remaining...)
+	if !v.hasTransformer() {
+		v.setTransformer(func(val any) (any, error) {
+			return cfg.coerceToInterval(val)
+		})
+	}
+	return v
+}
+
+func (c *timeConfig) coerceToInterval(val any) (TimeInterval, error) {
+	vo := reflect.Indirect(reflect.ValueOf(val))
+	if !vo.IsValid() {
+		return TimeInterval{}, InvalidTypeError
+	}
+
+	switch vo.Kind() {
+	case reflect.String:
+		start, end, ok := strings.Cut(strings.TrimSpace(vo.String()), "/")
+		if !ok {
+			return TimeInterval{}, InvalidIntervalError
+		}
+		return c.intervalFromParts(start, end)
+	case reflect.Map:
+		if vo.Type().Key().Kind() != reflect.String {
+			return TimeInterval{}, InvalidTypeError
+		}
+		start := vo.MapIndex(reflect.ValueOf("start"))
+		end := vo.MapIndex(reflect.ValueOf("end"))
+		if !start.IsValid() || !end.IsValid() {
+			return TimeInterval{}, InvalidIntervalError
+		}
+		return c.intervalFromParts(start.Interface(), end.Interface())
+	}
+	return TimeInterval{}, InvalidTypeError
+}
+
+// intervalFromParts accepts a duration in place of either the start or the end
+func (c *timeConfig) intervalFromParts(start, end any) (TimeInterval, error) {
+	startDuration, startIsDuration := c.intervalDuration(start)
+	endDuration, endIsDuration := c.intervalDuration(end)
+	switch {
+	case startIsDuration && endIsDuration:
+		return TimeInterval{}, InvalidIntervalError
+	case endIsDuration:
+		t, err := c.coerceToTime(start)
+		if err != nil {
+			return TimeInterval{}, err
+		}
+		return TimeInterval{Start: t, End: t.Add(endDuration)}, nil
+	case startIsDuration:
+		t, err := c.coerceToTime(end)
+		if err != nil {
+			return TimeInterval{}, err
+		}
+		return TimeInterval{Start: t.Add(-startDuration), End: t}, nil
+	}
+
+	startTime, err := c.coerceToTime(start)
+	if err != nil {
+		return TimeInterval{}, err
+	}
+	endTime, err := c.coerceToTime(end)
+	if err != nil {
+		return TimeInterval{}, err
+	}
+	return TimeInterval{Start: startTime, End: endTime}, nil
+}
+
+func (c *timeConfig) intervalDuration(val any) (time.Duration, bool) {
+	s, ok := val.(string)
+	if !ok || !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(s)), "P") {
+		return 0, false
+	}
+	d, err := parseISODuration(strings.TrimSpace(s))
+	return d, err == nil
+}
+
+var InvalidIntervalError = &parseError{message: "invalid interval", code: "invalid_interval"}
+
+var IntervalOrderError = &parseError{message: "interval ends before it starts", code: "interval_order"}
+
+func checkIntervalOrder(val *TimeInterval) *parseError {
+	if val != nil && val.End.Before(val.Start) {
+		return IntervalOrderError
+	}
+	return nil
+}
+
+// MaxSpan limits the length of an interval e.g. a report covering at most 90 days
+func MaxSpan(max time.Duration, message ...string) intervalValidatorOpt {
+	return func(val *TimeInterval) *parseError {
+		if val != nil && val.Duration() > max {
+			return timeError("interval_too_long", "interval too long", map[string]any{"max": max.String()}, message...)
+		}
+		return nil
+	}
+}
+
+func MinSpan(min time.Duration, message ...string) intervalValidatorOpt {
+	return func(val *TimeInterval) *parseError {
+		if val != nil && val.Duration() < min {
+			return timeError("interval_too_short", "interval too short", map[string]any{"min": min.String()}, message...)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,262 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"strings"
+	"time"
+
+	"golang.org/x/net/idna"
+)
+
+// limits from RFC 5321 section 4.5.3.1, the total is limited by the 256 octet path including the angle brackets
+const (
+	maxEmailLocalLength  = 64
+	maxEmailDomainLength = 255
+	maxEmailLength       = 254
+)
+
+// DefaultDomainCheckTimeout is the time allowed for each domain checker
+var DefaultDomainCheckTimeout = 5 * time.Second
+
+// EmailDomainChecker decides whether addresses at a domain are accepted e.g. by looking up MX records,
+// the domain is passed in its lower case ASCII form
+type EmailDomainChecker interface {
+	CheckDomain(ctx context.Context, domain string) error
+}
+
+type EmailDomainCheckerFunc func(ctx context.Context, domain string) error
+
+func (f EmailDomainCheckerFunc) CheckDomain(ctx context.Context, domain string) error {
+	return f(ctx, domain)
+}
+
+type emailConfig struct {
+	bare           bool
+	normalize      bool
+	canonicalGmail bool
+	checkers       []EmailDomainChecker
+	checkCtx       context.Context
+	timeout        time.Duration
+}
+
+type emailOpt func(c *emailConfig)
+
+// BareAddress only accepts a plain address e.g. john@example.com, display names, angle brackets and
+// domains without a dot are rejected
+func BareAddress() emailOpt {
+	return func(c *emailConfig) {
+		c.bare = true
+	}
+}
+
+// NormalizeEmail replaces the value with the bare address with the domain in lower case ASCII, international
+// domains are converted to punycode
+func NormalizeEmail() emailOpt {
+	return func(c *emailConfig) {
+		c.normalize = true
+	}
+}
+
+// CanonicalGmail removes dots and plus tags from Gmail addresses so that each mailbox has a single form,
+// it implies NormalizeEmail
+func CanonicalGmail() emailOpt {
+	return func(c *emailConfig) {
+		c.normalize = true
+		c.canonicalGmail = true
+	}
+}
+
+func WithDomainChecker(checker EmailDomainChecker) emailOpt {
+	return func(c *emailConfig) {
+		c.checkers = append(c.checkers, checker)
+	}
+}
+
+// WithDomainCheckContext sets the parent context of the domain checks e.g. the context of the request being
+// handled when the schema is built per request, a cancelled context fails the check
+func WithDomainCheckContext(ctx context.Context) emailOpt {
+	return func(c *emailConfig) {
+		c.checkCtx = ctx
+	}
+}
+
+// WithDomainCheckTimeout limits each domain checker, DefaultDomainCheckTimeout is used if it is zero
+func WithDomainCheckTimeout(timeout time.Duration) emailOpt {
+	return func(c *emailConfig) {
+		c.timeout = timeout
+	}
+}
+
+// Email validates an email address, it accepts the options above and an optional error message
+func Email(opts ...any) stringValidatorOpt {
+	cfg := &emailConfig{
+		checkers: make([]EmailDomainChecker, 0),
+		checkCtx: context.Background(),
+	}
+	message := make([]string, 0)
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case string:
+			message = append(message, opt)
+		case emailOpt:
+			opt(cfg)
+		}
+	}
+
+	return func(val *string) *parseError {
+		if val == nil {
+			return nil
+		}
+		local, domain, err := cfg.parse(*val)
+		if err != nil {
+			if len(message) > 0 {
+				return &parseError{message: message[0], inner: []error{err}, code: "invalid_email"}
+			}
+			return &parseError{message: "invalid email address", inner: []error{err}, code: "invalid_email"}
+		}
+
+		for _, checker := range cfg.checkers {
+			if err := cfg.checkDomain(checker, domain); err != nil {
+				if len(message) > 0 {
+					return &parseError{message: message[0], inner: []error{err}, code: "email_domain_rejected"}
+				}
+				return &parseError{message: "email domain not accepted", inner: []error{err}, code: "email_domain_rejected"}
+			}
+		}
+
+		if cfg.normalize {
+			if cfg.canonicalGmail {
+				local, domain = canonicalGmail(local, domain)
+			}
+			*val = local + "@" + domain
+		}
+		return nil
+	}
+}
+
+// parse returns the local part and the lower case ASCII domain
+func (c *emailConfig) parse(val string) (string, string, error) {
+	addr, err := mail.ParseAddress(val)
+	if err != nil {
+		return "", "", err
+	}
+	if c.bare && addr.Address != val {
+		return "", "", errors.New("not a bare address")
+	}
+
+	ix := strings.LastIndex(addr.Address, "@")
+	local, domain := addr.Address[:ix], addr.Address[ix+1:]
+
+	profile := idna.Punycode
+	if c.bare {
+		profile = idna.Lookup
+	}
+	domain, err = profile.ToASCII(strings.ToLower(domain))
+	if err != nil {
+		return "", "", err
+	}
+
+	switch {
+	case len(local) > maxEmailLocalLength:
+		return "", "", errors.New("local part is too long")
+	case len(domain) > maxEmailDomainLength:
+		return "", "", errors.New("domain is too long")
+	case len(local)+1+len(domain) > maxEmailLength:
+		return "", "", errors.New("address is too long")
+	case c.bare && (!strings.Contains(domain, ".") || !isHostname(domain)):
+		return "", "", errors.New("invalid domain")
+	}
+
+	return local, domain, nil
+}
+
+func (c *emailConfig) checkDomain(checker EmailDomainChecker, domain string) error {
+	timeout := c.timeout
+	if timeout <= 0 {
+		timeout = DefaultDomainCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(c.checkCtx, timeout)
+	defer cancel()
+	return checker.CheckDomain(ctx, domain)
+}
+
+func canonicalGmail(local, domain string) (string, string) {
+	if domain != "gmail.com" && domain != "googlemail.com" {
+		return local, domain
+	}
+	local, _, _ = strings.Cut(strings.ToLower(local), "+")
+	return strings.ReplaceAll(local, ".", ""), "gmail.com"
+}
+
+// MXResolver is satisfied by *net.Resolver, a stub can be used in tests
+type MXResolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+}
+
+// MXChecker rejects domains which have no MX records or which publish a null MX record (RFC 7505)
+type MXChecker struct {
+	Resolver MXResolver
+}
+
+func NewMXChecker(resolver MXResolver) *MXChecker {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &MXChecker{Resolver: resolver}
+}
+
+func (c *MXChecker) CheckDomain(ctx context.Context, domain string) error {
+	records, err := c.Resolver.LookupMX(ctx, domain)
+	if err != nil {
+		return fmt.Errorf("looking up MX records for %s: %w", domain, err)
+	}
+	if len(records) == 0 || (len(records) == 1 && strings.TrimSuffix(records[0].Host, ".") == "") {
+		return fmt.Errorf("%s does not accept email", domain)
+	}
+	return nil
+}
+
+// DomainBlocklist rejects the listed domains and their subdomains e.g. disposable email providers
+type DomainBlocklist map[string]bool
+
+func NewDomainBlocklist(domains ...string) DomainBlocklist {
+	b := make(DomainBlocklist)
+	for _, domain := range domains {
+		b[strings.ToLower(strings.TrimSpace(domain))] = true
+	}
+	return b
+}
+
+func (b DomainBlocklist) CheckDomain(ctx context.Context, domain string) error {
+	for d := domain; d > ""; {
+		if b[d] {
+			return fmt.Errorf("%s is blocked", domain)
+		}
+		_, parent, ok := strings.Cut(d, ".")
+		if !ok {
+			break
+		}
+		d = parent
+	}
+	return nil
+}
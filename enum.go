@@ -0,0 +1,207 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"golang.org/x/exp/constraints"
+)
+
+// enumOpt restricts a field to a set of typed constants, it is passed to the schema for the underlying type
+// e.g. String("plan", EnumOf(PlanFree, PlanPro)) and Unmarshal then writes the value into the named type
+type enumOpt[T comparable] struct {
+	values   []T
+	aliases  map[string]T
+	foldCase bool
+}
+
+// EnumOf accepts the values themselves or their names, the name of a value is the result of String() for types
+// implementing fmt.Stringer, otherwise the value formatted with fmt. If no values are given then T must implement
+// encoding.TextUnmarshaler and anything it accepts is allowed.
+func EnumOf[T comparable](values ...T) *enumOpt[T] {
+	return &enumOpt[T]{
+		values:  values,
+		aliases: make(map[string]T),
+	}
+}
+
+// EnumRange builds an enum from a sequence of integer constants e.g. those generated by stringer
+func EnumRange[T constraints.Integer](first, last T) *enumOpt[T] {
+	values := make([]T, 0)
+	for v := first; v <= last; v++ {
+		values = append(values, v)
+		if v == last {
+			break
+		}
+	}
+	return EnumOf(values...)
+}
+
+// CaseInsensitive matches names and aliases ignoring case
+func (e *enumOpt[T]) CaseInsensitive() *enumOpt[T] {
+	e.foldCase = true
+	return e
+}
+
+// Alias accepts an alternative name for a value e.g. Alias("premium", PlanPro)
+func (e *enumOpt[T]) Alias(name string, value T) *enumOpt[T] {
+	e.aliases[name] = value
+	return e
+}
+
+func (e *enumOpt[T]) apply(v genericValidatorOptReceiver) error {
+	if len(e.values) == 0 {
+		if _, ok := any(new(T)).(encoding.TextUnmarshaler); !ok {
+			return fmt.Errorf("enum of %T has no values", *new(T))
+		}
+	}
+	// the transformer converts names to values and the option checks values which are already of the field type
+	v.setTransformer(func(val any) (any, error) {
+		match, err := e.check(val)
+		if err != nil {
+			return nil, err
+		}
+		return match, nil
+	})
+	v.addOption(e.check)
+	return nil
+}
+
+func (e *enumOpt[T]) check(val any) (any, *parseError) {
+	if match, ok := e.match(val); ok {
+		return match, nil
+	}
+	return nil, &parseError{
+		message: "value not found in enum",
+		code:    "invalid_enum",
+		params:  map[string]any{"value": fmt.Sprint(val), "allowed": e.names()},
+	}
+}
+
+func (e *enumOpt[T]) match(val any) (T, bool) {
+	var zero T
+	to := reflect.TypeOf(zero)
+
+	vo := reflect.Indirect(reflect.ValueOf(val))
+	if !vo.IsValid() {
+		return zero, false
+	}
+	val = vo.Interface()
+
+	// without a list of values UnmarshalText decides what is allowed
+	if len(e.values) == 0 {
+		return unmarshalEnumText[T](fmt.Sprint(val))
+	}
+
+	if typed, ok := val.(T); ok && e.allowed(typed) {
+		return typed, true
+	}
+
+	if vo.Kind() == reflect.String {
+		s := strings.TrimSpace(vo.String())
+		for _, v := range e.values {
+			if e.equal(enumName(v), s) {
+				return v, true
+			}
+		}
+		for name, v := range e.aliases {
+			if e.equal(name, s) {
+				return v, true
+			}
+		}
+		if typed, ok := unmarshalEnumText[T](s); ok && e.allowed(typed) {
+			return typed, true
+		}
+		// form values of integer enums arrive as strings
+		if isNumeric(zero) {
+			if typed, err := convertJSONNumber[T](json.Number(s)); err == nil {
+				return *typed, e.allowed(*typed)
+			}
+		}
+	}
+
+	// numbers and values of other named types are converted to T and compared
+	if num, ok := val.(json.Number); ok {
+		typed, err := convertJSONNumber[T](num)
+		if err != nil {
+			return zero, false
+		}
+		return *typed, e.allowed(*typed)
+	}
+	if vo.Kind() == to.Kind() && vo.Type().ConvertibleTo(to) {
+		typed := vo.Convert(to).Interface().(T)
+		return typed, e.allowed(typed)
+	}
+	if isNumeric(val) && isNumeric(zero) {
+		if typed, err := convertJSONNumber[T](json.Number(fmt.Sprint(val))); err == nil {
+			return *typed, e.allowed(*typed)
+		}
+	}
+
+	return zero, false
+}
+
+func (e *enumOpt[T]) allowed(val T) bool {
+	if len(e.values) == 0 {
+		return true
+	}
+	for _, v := range e.values {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *enumOpt[T]) equal(a, b string) bool {
+	if e.foldCase {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+func (e *enumOpt[T]) names() []string {
+	names := make([]string, len(e.values))
+	for i, v := range e.values {
+		names[i] = enumName(v)
+	}
+	return names
+}
+
+func enumName(val any) string {
+	if s, ok := val.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprint(val)
+}
+
+func unmarshalEnumText[T any](s string) (T, bool) {
+	typed := new(T)
+	u, ok := any(typed).(encoding.TextUnmarshaler)
+	if !ok {
+		return *typed, false
+	}
+	if err := u.UnmarshalText([]byte(s)); err != nil {
+		return *typed, false
+	}
+	return *typed, true
+}
@@ -0,0 +1,372 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	e164Pattern     = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+	hexPattern      = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+	hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+	slugPattern     = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	ulidPattern     = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{25}$`)
+	ksuidPattern    = regexp.MustCompile(`^[0-9A-Za-z]{27}$`)
+	nanoIDPattern   = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	labelPattern    = regexp.MustCompile(`^[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+	// see https://semver.org/#is-there-a-suggested-regular-expression-regex-to-check-a-semver-string
+	semVerPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+		`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+		`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+)
+
+var hierarchicalSchemes = []string{"http", "https", "ftp", "ws", "wss"}
+
+// formatOpt builds a validator for a string format, the error carries the code so callers can tell the formats apart
+func formatOpt(code string, defaultMessage string, valid func(val string) bool, message ...string) stringValidatorOpt {
+	return func(val *string) *parseError {
+		if val == nil {
+			return nil
+		}
+		if !valid(*val) {
+			if len(message) > 0 {
+				return &parseError{message: message[0], code: code}
+			}
+			return &parseError{message: defaultMessage, code: code}
+		}
+		return nil
+	}
+}
+
+// URL checks for an absolute http or https URL, use URLSchemes to allow other schemes
+func URL(message ...string) stringValidatorOpt {
+	return URLSchemes(nil, message...)
+}
+
+// URLSchemes checks for an absolute URL with one of the schemes, nil schemes default to http and https
+func URLSchemes(schemes []string, message ...string) stringValidatorOpt {
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https"}
+	}
+	return formatOpt("invalid_url", "invalid URL", func(val string) bool {
+		u, err := url.Parse(val)
+		if err != nil || !slices.Contains(schemes, strings.ToLower(u.Scheme)) {
+			return false
+		}
+		// schemes such as mailto: have no host
+		return u.Host > "" || (u.Opaque > "" && !slices.Contains(hierarchicalSchemes, u.Scheme))
+	}, message...)
+}
+
+// Hostname checks for a DNS host name as described in RFC 1123
+func Hostname(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_hostname", "invalid host name", isHostname, message...)
+}
+
+func isHostname(val string) bool {
+	if len(val) == 0 || len(val) > 253 {
+		return false
+	}
+	labels := strings.Split(val, ".")
+	for _, label := range labels {
+		if !labelPattern.MatchString(label) {
+			return false
+		}
+	}
+	// a top level domain is never numeric, this stops IPv4 addresses passing as host names
+	return strings.Trim(labels[len(labels)-1], "0123456789") > ""
+}
+
+func IP(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_ip", "invalid IP address", func(val string) bool {
+		_, err := netip.ParseAddr(val)
+		return err == nil
+	}, message...)
+}
+
+func IPv4(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_ipv4", "invalid IPv4 address", func(val string) bool {
+		addr, err := netip.ParseAddr(val)
+		return err == nil && addr.Is4()
+	}, message...)
+}
+
+func IPv6(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_ipv6", "invalid IPv6 address", func(val string) bool {
+		addr, err := netip.ParseAddr(val)
+		return err == nil && addr.Is6()
+	}, message...)
+}
+
+// CIDR checks for an IP prefix e.g. 10.0.0.0/8
+func CIDR(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_cidr", "invalid CIDR", func(val string) bool {
+		_, err := netip.ParsePrefix(val)
+		return err == nil
+	}, message...)
+}
+
+// MAC checks for a hardware address e.g. 00:00:5e:00:53:01
+func MAC(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_mac", "invalid MAC address", func(val string) bool {
+		_, err := net.ParseMAC(val)
+		return err == nil
+	}, message...)
+}
+
+// E164Phone checks for an international phone number e.g. +442071234567
+func E164Phone(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_phone", "invalid phone number", e164Pattern.MatchString, message...)
+}
+
+// Base64 checks for standard base64 with padding
+func Base64(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_base64", "invalid base64", func(val string) bool {
+		_, err := base64.StdEncoding.DecodeString(val)
+		return err == nil
+	}, message...)
+}
+
+// Base64URL checks for URL safe base64, padding is optional
+func Base64URL(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_base64url", "invalid base64url", isBase64URL, message...)
+}
+
+func isBase64URL(val string) bool {
+	_, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(val, "="))
+	return err == nil
+}
+
+func Hex(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_hex", "invalid hex string", hexPattern.MatchString, message...)
+}
+
+// HexColor checks for a CSS colour e.g. #fff or #ff8800cc
+func HexColor(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_hex_color", "invalid hex colour", hexColorPattern.MatchString, message...)
+}
+
+// Slug checks for lower case words separated by single hyphens e.g. my-first-post
+func Slug(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_slug", "invalid slug", slugPattern.MatchString, message...)
+}
+
+// SemVer checks for a semantic version e.g. 1.2.3-beta.1, a leading v is not allowed
+func SemVer(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_semver", "invalid semantic version", semVerPattern.MatchString, message...)
+}
+
+// JSONString checks that the string contains a valid JSON document
+func JSONString(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_json", "invalid JSON", func(val string) bool {
+		return json.Valid([]byte(val))
+	}, message...)
+}
+
+// ISO8601Date checks for a calendar date e.g. 2024-02-29
+func ISO8601Date(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_date", "invalid date", func(val string) bool {
+		_, err := time.Parse(time.DateOnly, val)
+		return err == nil
+	}, message...)
+}
+
+func ULID(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_ulid", "invalid ULID", ulidPattern.MatchString, message...)
+}
+
+// KSUID checks for a K-Sortable Unique ID e.g. 0ujtsYcgvSTl8PAuAdqWYSMnLOv
+func KSUID(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_ksuid", "invalid KSUID", func(val string) bool {
+		_, ok := ksuidTime(val)
+		return ok
+	}, message...)
+}
+
+// NanoID checks for an id using the default nanoid alphabet, the length defaults to 21 if it is zero
+func NanoID(length int, message ...string) stringValidatorOpt {
+	if length == 0 {
+		length = 21
+	}
+	return formatOpt("invalid_nanoid", "invalid nanoid", func(val string) bool {
+		return len(val) == length && nanoIDPattern.MatchString(val)
+	}, message...)
+}
+
+// IDNotInFuture rejects ULIDs and KSUIDs whose timestamp is later than now plus the allowed clock skew,
+// other strings have no timestamp and are rejected
+func IDNotInFuture(skew time.Duration, message ...string) clockedOpt[string] {
+	return func(now time.Time, val string) *parseError {
+		created, ok := ulidTime(val)
+		if !ok {
+			created, ok = ksuidTime(val)
+		}
+		if ok && !created.After(now.Add(skew)) {
+			return nil
+		}
+		return idTimeError(message...)
+	}
+}
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidTime decodes the 48 bit millisecond timestamp held in the first 10 characters
+func ulidTime(val string) (time.Time, bool) {
+	if !ulidPattern.MatchString(val) {
+		return time.Time{}, false
+	}
+	var ms int64
+	for _, r := range strings.ToUpper(val[:10]) {
+		ms = ms<<5 | int64(strings.IndexRune(crockfordAlphabet, r))
+	}
+	return time.UnixMilli(ms), true
+}
+
+// ksuidEpoch is the start of KSUID time, 2014-05-13T16:53:20Z
+const ksuidEpoch = 1400000000
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ksuidTime decodes the 20 byte value and reads the timestamp from the first 4 bytes
+func ksuidTime(val string) (time.Time, bool) {
+	if !ksuidPattern.MatchString(val) {
+		return time.Time{}, false
+	}
+	n := new(big.Int)
+	for _, r := range val {
+		n.Mul(n, big.NewInt(62))
+		n.Add(n, big.NewInt(int64(strings.IndexRune(base62Alphabet, r))))
+	}
+	if n.BitLen() > 160 {
+		return time.Time{}, false
+	}
+	seconds := new(big.Int).Rsh(n, 128).Int64()
+	return time.Unix(ksuidEpoch+seconds, 0), true
+}
+
+// JWTShape checks that a token has a JSON header and payload, the signature is not verified
+func JWTShape(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_jwt", "invalid JWT", isJWTShape, message...)
+}
+
+func isJWTShape(val string) bool {
+	parts := strings.Split(val, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	for _, part := range parts[:2] {
+		data, err := base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			return false
+		}
+		var obj map[string]any
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return false
+		}
+	}
+	_, err := base64.RawURLEncoding.DecodeString(parts[2])
+	return err == nil
+}
+
+// Luhn checks a payment card number, spaces and hyphens between digits are allowed
+func Luhn(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_card_number", "invalid card number", isLuhn, message...)
+}
+
+func isLuhn(val string) bool {
+	digits := make([]int, 0, len(val))
+	for i, r := range val {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = append(digits, int(r-'0'))
+		case (r == ' ' || r == '-') && i > 0 && i < len(val)-1:
+		default:
+			return false
+		}
+	}
+	if len(digits) < 12 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	for i := range digits {
+		d := digits[len(digits)-1-i]
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// IBAN checks the length for the country and the check digits, spaces are allowed
+func IBAN(message ...string) stringValidatorOpt {
+	return formatOpt("invalid_iban", "invalid IBAN", isIBAN, message...)
+}
+
+func isIBAN(val string) bool {
+	val = strings.ToUpper(strings.ReplaceAll(val, " ", ""))
+	if len(val) < 4 {
+		return false
+	}
+	length, ok := ibanLengths[val[:2]]
+	if !ok || len(val) != length {
+		return false
+	}
+
+	// move the country and check digits to the end then convert letters to numbers, A = 10 ... Z = 35
+	rearranged := new(strings.Builder)
+	for _, r := range val[4:] + val[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			rearranged.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			rearranged.WriteString(strconv.Itoa(int(r - 'A' + 10)))
+		default:
+			return false
+		}
+	}
+	n, ok := new(big.Int).SetString(rearranged.String(), 10)
+	return ok && n.Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+// ibanLengths is the length of an IBAN for each country in the SWIFT IBAN registry
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22, "BH": 22, "BI": 27,
+	"BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22, "DJ": 27, "DK": 18, "DO": 28,
+	"EE": 20, "EG": 29, "ES": 24, "FI": 18, "FK": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23,
+	"GL": 18, "GR": 27, "GT": 28, "HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27,
+	"JO": 30, "KW": 30, "KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "LY": 25,
+	"MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20, "MR": 27, "MT": 31, "MU": 30, "NI": 28, "NL": 18,
+	"NO": 15, "OM": 23, "PK": 24, "PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "RU": 33,
+	"SA": 24, "SC": 31, "SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "SO": 23, "ST": 25, "SV": 28,
+	"TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
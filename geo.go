@@ -0,0 +1,623 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// GeoPoint is a WGS 84 coordinate in degrees
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+var InvalidCoordinatesError = &parseError{message: "invalid coordinates", code: "invalid_coordinates"}
+
+var (
+	latitudeValidator   = Float64(Finite(), Min(-90), Max(90))
+	longitudeValidator  = Float64(Finite(), Min(-180), Max(180))
+	coordinateValidator = Float64(Finite())
+	geoPointSchema      = Object().
+				Float64("lat", Required(), Finite(), Min(-90), Max(90)).
+				Float64("lng", Required(), Finite(), Min(-180), Max(180))
+)
+
+// pointOpt is applied to a coordinate or to every position of a geometry
+type pointOpt func(p GeoPoint) *parseError
+
+// WithinBoundingBox accepts points inside the box given in GeoJSON order, if west is greater than east the box
+// crosses the antimeridian
+func WithinBoundingBox(west, south, east, north float64, message ...string) pointOpt {
+	return func(p GeoPoint) *parseError {
+		inLng := p.Lng >= west && p.Lng <= east
+		if west > east {
+			inLng = p.Lng >= west || p.Lng <= east
+		}
+		if !inLng || p.Lat < south || p.Lat > north {
+			params := map[string]any{"bbox": []float64{west, south, east, north}}
+			if len(message) > 0 {
+				return &parseError{message: message[0], code: "outside_bounding_box", params: params}
+			}
+			return &parseError{message: "location is outside the allowed area", code: "outside_bounding_box", params: params}
+		}
+		return nil
+	}
+}
+
+func splitPointOpts(opts []any) ([]pointOpt, []any) {
+	points := make([]pointOpt, 0)
+	remaining := make([]any, 0, len(opts))
+	for _, opt := range opts {
+		if opt, ok := opt.(pointOpt); ok {
+			points = append(points, opt)
+			continue
+		}
+		remaining = append(remaining, opt)
+	}
+	return points, remaining
+}
+
+// LatLng parses coordinates from objects e.g. {"lat": 51.5, "lng": -0.12}, GeoJSON positions e.g. [-0.12, 51.5]
+// and strings e.g. "51.5,-0.12", note that arrays put the longitude first
+func LatLng(opts ...any) genericValidator[GeoPoint] {
+	return latLngValidatorFactory(opts...)
+}
+
+func latLngValidatorFactory(opts ...any) validatorWithOpts[GeoPoint] {
+	points, remaining := splitPointOpts(opts)
+	remaining = append(remaining, parseOpt[GeoPoint](func(val *GeoPoint) *parseError {
+		if val == nil {
+			return nil
+		}
+		for _, check := range points {
+			if err := check(*val); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	v := validatorFactory[GeoPoint](remaining...)
+	if !v.hasTransformer() {
+		v.setTransformer(func(val any) (any, error) {
+			return coerceToGeoPoint(val)
+		})
+	}
+	return v
+}
+
+func coerceToGeoPoint(val any) (GeoPoint, error) {
+	vo := reflect.Indirect(reflect.ValueOf(val))
+	if !vo.IsValid() {
+		return GeoPoint{}, InvalidTypeError
+	}
+
+	switch vo.Kind() {
+	case reflect.String:
+		lat, lng, ok := strings.Cut(vo.String(), ",")
+		if !ok {
+			return GeoPoint{}, InvalidCoordinatesError
+		}
+		return parseGeoPoint(strings.TrimSpace(lat), strings.TrimSpace(lng))
+	case reflect.Slice, reflect.Array:
+		return coerceToPosition(vo)
+	case reflect.Map:
+		res := geoPointSchema.Parse(val)
+		if !res.IsValid() {
+			return GeoPoint{}, &parseError{message: InvalidCoordinatesError.message, code: InvalidCoordinatesError.code, inner: errorList(res.Errors())}
+		}
+		return GeoPoint{Lat: res.GetField("lat").Get().(float64), Lng: res.GetField("lng").Get().(float64)}, nil
+	}
+	return GeoPoint{}, InvalidTypeError
+}
+
+// coerceToPosition reads a GeoJSON position and checks its range
+func coerceToPosition(vo reflect.Value) (GeoPoint, error) {
+	p, err := readPosition(vo)
+	if err != nil {
+		return GeoPoint{}, err
+	}
+	return parseGeoPoint(p.Lat, p.Lng)
+}
+
+// readPosition reads the numbers of a GeoJSON position without checking their range, an altitude may follow the
+// longitude and latitude and is ignored
+func readPosition(vo reflect.Value) (GeoPoint, error) {
+	if (vo.Kind() != reflect.Slice && vo.Kind() != reflect.Array) || vo.Len() < 2 || vo.Len() > 3 {
+		return GeoPoint{}, InvalidCoordinatesError
+	}
+	numbers := make([]float64, vo.Len())
+	for i := range numbers {
+		item := vo.Index(i).Interface()
+		res := coordinateValidator.Parse(item)
+		if item == nil || !res.IsValid() {
+			return GeoPoint{}, InvalidCoordinatesError
+		}
+		numbers[i] = res.Get()
+	}
+	return GeoPoint{Lat: numbers[1], Lng: numbers[0]}, nil
+}
+
+func parseGeoPoint(lat, lng any) (GeoPoint, error) {
+	latRes := latitudeValidator.Parse(lat)
+	lngRes := longitudeValidator.Parse(lng)
+	if lat == nil || lng == nil || !latRes.IsValid() || !lngRes.IsValid() {
+		inner := errorList(append(latRes.Errors(), lngRes.Errors()...))
+		return GeoPoint{}, &parseError{message: InvalidCoordinatesError.message, code: InvalidCoordinatesError.code, inner: inner}
+	}
+	return GeoPoint{Lat: latRes.Get(), Lng: lngRes.Get()}, nil
+}
+
+func errorList(errs []*parseError) []error {
+	list := make([]error, len(errs))
+	for i, err := range errs {
+		list[i] = err
+	}
+	return list
+}
+
+// GeoJSON geometry types accepted by GeoJSON()
+const (
+	GeometryPoint        = "Point"
+	GeometryLineString   = "LineString"
+	GeometryPolygon      = "Polygon"
+	GeometryMultiPolygon = "MultiPolygon"
+)
+
+// Geometry is a GeoJSON geometry, only the coordinates for its type are set
+type Geometry struct {
+	Type         string
+	Point        GeoPoint
+	LineString   []GeoPoint
+	Polygon      [][]GeoPoint
+	MultiPolygon [][][]GeoPoint
+}
+
+var InvalidGeometryError = &parseError{message: "invalid geometry", code: "invalid_geometry"}
+
+type geoOpt func(c *geoConfig)
+
+type geoConfig struct {
+	closeRings         bool
+	rewind             bool
+	maxVertices        int
+	maxVerticesMessage []string
+}
+
+// CloseRings repeats the first position of a polygon ring at the end when it is missing
+func CloseRings() geoOpt {
+	return func(c *geoConfig) {
+		c.closeRings = true
+	}
+}
+
+// RewindRings reverses polygon rings to follow the right hand rule, exterior rings counterclockwise and holes clockwise
+func RewindRings() geoOpt {
+	return func(c *geoConfig) {
+		c.rewind = true
+	}
+}
+
+// RequireRightHandRule rejects polygons with clockwise exterior rings or counterclockwise holes
+func RequireRightHandRule(message ...string) parseOpt[Geometry] {
+	return func(val *Geometry) *parseError {
+		if val == nil {
+			return nil
+		}
+		for i, polygon := range val.polygons() {
+			for j, ring := range polygon {
+				if (j == 0) != (ringArea(ring) > 0) {
+					return geoError("invalid_winding", "polygon rings must follow the right hand rule", map[string]any{"polygon": i, "ring": j}, message...)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// MaxVertices limits the total number of positions in a geometry, the positions are counted before any of them are
+// parsed so oversized geometries are rejected cheaply
+func MaxVertices(max int, message ...string) geoOpt {
+	return func(c *geoConfig) {
+		c.maxVertices = max
+		c.maxVerticesMessage = message
+	}
+}
+
+func (c *geoConfig) checkVertices(count int) *parseError {
+	if c.maxVertices > 0 && count > c.maxVertices {
+		return geoError("too_many_vertices", "geometry has too many vertices", map[string]any{"max": c.maxVertices}, c.maxVerticesMessage...)
+	}
+	return nil
+}
+
+// GeometryTypes restricts the accepted geometry types e.g. GeometryTypes(GeometryPolygon, GeometryMultiPolygon)
+func GeometryTypes(types ...string) parseOpt[Geometry] {
+	return func(val *Geometry) *parseError {
+		if val == nil || slices.Contains(types, val.Type) {
+			return nil
+		}
+		return geoError("geometry_type_not_allowed", "geometry type is not allowed", map[string]any{"types": types})
+	}
+}
+
+func geoError(code, defaultMessage string, params map[string]any, message ...string) *parseError {
+	if len(message) > 0 {
+		return &parseError{message: message[0], code: code, params: params}
+	}
+	return &parseError{message: defaultMessage, code: code, params: params}
+}
+
+// Vertices counts the positions in the geometry
+func (g Geometry) Vertices() int {
+	switch g.Type {
+	case GeometryPoint:
+		return 1
+	case GeometryLineString:
+		return len(g.LineString)
+	}
+	count := 0
+	for _, polygon := range g.polygons() {
+		for _, ring := range polygon {
+			count += len(ring)
+		}
+	}
+	return count
+}
+
+func (g Geometry) polygons() [][][]GeoPoint {
+	switch g.Type {
+	case GeometryPolygon:
+		return [][][]GeoPoint{g.Polygon}
+	case GeometryMultiPolygon:
+		return g.MultiPolygon
+	}
+	return nil
+}
+
+func (g Geometry) positions() []GeoPoint {
+	switch g.Type {
+	case GeometryPoint:
+		return []GeoPoint{g.Point}
+	case GeometryLineString:
+		return g.LineString
+	}
+	positions := make([]GeoPoint, 0, g.Vertices())
+	for _, polygon := range g.polygons() {
+		for _, ring := range polygon {
+			positions = append(positions, ring...)
+		}
+	}
+	return positions
+}
+
+// UnmarshalJSON reads a GeoJSON geometry, the structure and coordinates are checked in the same way as GeoJSON()
+// without any options
+func (g *Geometry) UnmarshalJSON(data []byte) error {
+	parsed, err := decodeGeometry(&geoConfig{}, data)
+	if err != nil {
+		return err
+	}
+	if perr := checkGeometry(parsed); perr != nil {
+		return perr
+	}
+	*g = parsed
+	return nil
+}
+
+// MarshalJSON writes the geometry as GeoJSON
+func (g Geometry) MarshalJSON() ([]byte, error) {
+	var coordinates any
+	switch g.Type {
+	case GeometryPoint:
+		coordinates = positionJSON(g.Point)
+	case GeometryLineString:
+		coordinates = ringJSON(g.LineString)
+	case GeometryPolygon:
+		coordinates = polygonJSON(g.Polygon)
+	case GeometryMultiPolygon:
+		polygons := make([][][][2]float64, len(g.MultiPolygon))
+		for i, polygon := range g.MultiPolygon {
+			polygons[i] = polygonJSON(polygon)
+		}
+		coordinates = polygons
+	default:
+		return nil, InvalidGeometryError
+	}
+	return json.Marshal(map[string]any{"type": g.Type, "coordinates": coordinates})
+}
+
+func positionJSON(p GeoPoint) [2]float64 {
+	return [2]float64{p.Lng, p.Lat}
+}
+
+func ringJSON(ring []GeoPoint) [][2]float64 {
+	positions := make([][2]float64, len(ring))
+	for i, p := range ring {
+		positions[i] = positionJSON(p)
+	}
+	return positions
+}
+
+func polygonJSON(polygon [][]GeoPoint) [][][2]float64 {
+	rings := make([][][2]float64, len(polygon))
+	for i, ring := range polygon {
+		rings[i] = ringJSON(ring)
+	}
+	return rings
+}
+
+// GeoJSON parses Point, LineString, Polygon and MultiPolygon geometries from maps or JSON, polygon rings must be
+// closed and have at least four positions
+func GeoJSON(opts ...any) genericValidator[Geometry] {
+	return geoJSONValidatorFactory(opts...)
+}
+
+func geoJSONValidatorFactory(opts ...any) validatorWithOpts[Geometry] {
+	cfg := &geoConfig{}
+	points, remaining := splitPointOpts(opts)
+	opts = remaining
+	remaining = make([]any, 0, len(opts)+1)
+	for _, opt := range opts {
+		if opt, ok := opt.(geoOpt); ok {
+			opt(cfg)
+			continue
+		}
+		remaining = append(remaining, opt)
+	}
+
+	// the shape is checked first so that geometries passed as a Geometry are checked too
+	remaining = append([]any{parseOpt[Geometry](func(val *Geometry) *parseError {
+		if val == nil {
+			return nil
+		}
+		// geometries passed as a Geometry haven't been through the transformer
+		if err := cfg.checkVertices(val.Vertices()); err != nil {
+			return err
+		}
+		cfg.normalize(val)
+		if err := checkGeometry(*val); err != nil {
+			return err
+		}
+		for _, p := range val.positions() {
+			for _, check := range points {
+				if err := check(p); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})}, remaining...)
+
+	v := validatorFactory[Geometry](remaining...)
+	if !v.hasTransformer() {
+		v.setTransformer(func(val any) (any, error) {
+			return cfg.coerceToGeometry(val)
+		})
+	}
+	return v
+}
+
+func (c *geoConfig) normalize(g *Geometry) {
+	if !c.closeRings && !c.rewind {
+		return
+	}
+	// the rings are copied so that the caller's geometry isn't modified
+	normalizePolygon := func(polygon [][]GeoPoint) [][]GeoPoint {
+		rings := make([][]GeoPoint, len(polygon))
+		for i, ring := range polygon {
+			ring = slices.Clone(ring)
+			if c.closeRings && len(ring) > 0 && ring[0] != ring[len(ring)-1] {
+				ring = append(ring, ring[0])
+			}
+			if c.rewind && (i == 0) != (ringArea(ring) > 0) {
+				slices.Reverse(ring)
+			}
+			rings[i] = ring
+		}
+		return rings
+	}
+	switch g.Type {
+	case GeometryPolygon:
+		g.Polygon = normalizePolygon(g.Polygon)
+	case GeometryMultiPolygon:
+		polygons := make([][][]GeoPoint, len(g.MultiPolygon))
+		for i, polygon := range g.MultiPolygon {
+			polygons[i] = normalizePolygon(polygon)
+		}
+		g.MultiPolygon = polygons
+	}
+}
+
+// ringArea is the signed area of a ring in square degrees, positive when the ring is counterclockwise
+func ringArea(ring []GeoPoint) float64 {
+	area := 0.0
+	for i := 0; i+1 < len(ring); i++ {
+		area += ring[i].Lng*ring[i+1].Lat - ring[i+1].Lng*ring[i].Lat
+	}
+	return area / 2
+}
+
+func checkGeometry(g Geometry) *parseError {
+	switch g.Type {
+	case GeometryPoint:
+		return checkPositions([]GeoPoint{g.Point})
+	case GeometryLineString:
+		if len(g.LineString) < 2 {
+			return geoError("too_few_positions", "a line must have at least two positions", map[string]any{"min": 2})
+		}
+		return checkPositions(g.LineString)
+	case GeometryPolygon, GeometryMultiPolygon:
+		polygons := g.polygons()
+		if len(polygons) == 0 {
+			return geoError("too_few_positions", "a multipolygon must have at least one polygon", map[string]any{"min": 1})
+		}
+		for i, polygon := range polygons {
+			if len(polygon) == 0 {
+				return geoError("too_few_positions", "a polygon must have at least one ring", map[string]any{"polygon": i, "min": 1})
+			}
+			for j, ring := range polygon {
+				if len(ring) < 4 {
+					return geoError("too_few_positions", "a polygon ring must have at least four positions", map[string]any{"polygon": i, "ring": j, "min": 4})
+				}
+				if ring[0] != ring[len(ring)-1] {
+					return geoError("ring_not_closed", "the first and last positions of a polygon ring must be the same", map[string]any{"polygon": i, "ring": j})
+				}
+				if err := checkPositions(ring); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return InvalidGeometryError
+}
+
+// checkPositions checks the range of positions which have already been read as numbers
+func checkPositions(positions []GeoPoint) *parseError {
+	for _, p := range positions {
+		if !(p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180) {
+			return &parseError{message: InvalidCoordinatesError.message, code: InvalidCoordinatesError.code, params: map[string]any{"lat": p.Lat, "lng": p.Lng}, origin: InvalidCoordinatesError}
+		}
+	}
+	return nil
+}
+
+func (c *geoConfig) coerceToGeometry(val any) (Geometry, error) {
+	switch val := val.(type) {
+	case string:
+		return decodeGeometry(c, []byte(val))
+	case []byte:
+		return decodeGeometry(c, val)
+	case json.RawMessage:
+		return decodeGeometry(c, val)
+	}
+
+	vo := reflect.Indirect(reflect.ValueOf(val))
+	if vo.Kind() != reflect.Map || vo.Type().Key().Kind() != reflect.String {
+		return Geometry{}, InvalidTypeError
+	}
+	lookup := func(key string) any {
+		item := vo.MapIndex(reflect.ValueOf(key).Convert(vo.Type().Key()))
+		if !item.IsValid() {
+			return nil
+		}
+		return item.Interface()
+	}
+
+	typ, ok := lookup("type").(string)
+	if !ok {
+		return Geometry{}, InvalidGeometryError
+	}
+	coordinates := lookup("coordinates")
+	depth, ok := geometryDepths[typ]
+	if !ok {
+		return Geometry{}, geoError("invalid_geometry_type", "unsupported geometry type", map[string]any{"type": typ})
+	}
+	if err := c.checkVertices(countPositions(coordinates, depth)); err != nil {
+		return Geometry{}, err
+	}
+
+	g := Geometry{Type: typ}
+	var err error
+	switch typ {
+	case GeometryPoint:
+		g.Point, err = readPosition(reflect.Indirect(reflect.ValueOf(coordinates)))
+	case GeometryLineString:
+		g.LineString, err = coerceToPositions(coordinates)
+	case GeometryPolygon:
+		g.Polygon, err = coerceToRings(coordinates)
+	case GeometryMultiPolygon:
+		err = eachItem(coordinates, func(item any) error {
+			polygon, err := coerceToRings(item)
+			g.MultiPolygon = append(g.MultiPolygon, polygon)
+			return err
+		})
+	}
+	if err != nil {
+		return Geometry{}, err
+	}
+	return g, nil
+}
+
+// geometryDepths is the nesting of positions in the coordinates of each geometry type
+var geometryDepths = map[string]int{GeometryPoint: 0, GeometryLineString: 1, GeometryPolygon: 2, GeometryMultiPolygon: 3}
+
+// countPositions counts the positions without reading them, anything which isn't an array counts as nothing
+func countPositions(val any, depth int) int {
+	if depth == 0 {
+		return 1
+	}
+	vo := reflect.Indirect(reflect.ValueOf(val))
+	if vo.Kind() != reflect.Slice && vo.Kind() != reflect.Array {
+		return 0
+	}
+	if depth == 1 {
+		return vo.Len()
+	}
+	count := 0
+	for i := 0; i < vo.Len(); i++ {
+		count += countPositions(vo.Index(i).Interface(), depth-1)
+	}
+	return count
+}
+
+func decodeGeometry(c *geoConfig, data []byte) (Geometry, error) {
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return Geometry{}, InvalidGeometryError
+	}
+	return c.coerceToGeometry(obj)
+}
+
+func coerceToPositions(val any) ([]GeoPoint, error) {
+	positions := make([]GeoPoint, 0)
+	err := eachItem(val, func(item any) error {
+		p, err := readPosition(reflect.Indirect(reflect.ValueOf(item)))
+		positions = append(positions, p)
+		return err
+	})
+	return positions, err
+}
+
+func coerceToRings(val any) ([][]GeoPoint, error) {
+	rings := make([][]GeoPoint, 0)
+	err := eachItem(val, func(item any) error {
+		ring, err := coerceToPositions(item)
+		rings = append(rings, ring)
+		return err
+	})
+	return rings, err
+}
+
+func eachItem(val any, fn func(item any) error) error {
+	vo := reflect.Indirect(reflect.ValueOf(val))
+	if vo.Kind() != reflect.Slice && vo.Kind() != reflect.Array {
+		return InvalidGeometryError
+	}
+	for i := 0; i < vo.Len(); i++ {
+		if err := fn(vo.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,11 +1,14 @@
 module github.com/jdudmesh/ursa
 
-go 1.21.3
+go 1.23
 
 require (
-	github.com/google/uuid v1.4.0
+	github.com/google/uuid v1.6.0
+	github.com/rivo/uniseg v0.4.7
 	github.com/stretchr/testify v1.8.4
 	golang.org/x/exp v0.0.0-20231006140011-7918f672742d
+	golang.org/x/net v0.33.0
+	golang.org/x/text v0.21.0
 )
 
 require (
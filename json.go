@@ -0,0 +1,222 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+)
+
+var JSONTooDeepError = &parseError{
+	message: "JSON value is nested too deeply",
+}
+
+var JSONArrayTooLongError = &parseError{
+	message: "JSON array has too many elements",
+}
+
+var JSONTooManyKeysError = &parseError{
+	message: "JSON object has too many keys",
+}
+
+var JSONDuplicateKeyError = &parseError{
+	message: "JSON object has a duplicate key",
+}
+
+var JSONTrailingDataError = &parseError{
+	message: "unexpected data after JSON value",
+}
+
+// jsonLimits are enforced while the JSON is decoded so oversized documents are rejected before they are built in memory
+type jsonLimits struct {
+	maxDepth             int
+	maxArrayLength       int
+	maxObjectKeys        int
+	rejectDuplicateKeys  bool
+	disallowTrailingData bool
+}
+
+// MaxJSONDepth limits the nesting of objects and arrays, it applies to every body format and defaults to DefaultMaxDecodeDepth
+func MaxJSONDepth(depth int) objectValidatorOpt {
+	return func(o *objectValidator) error {
+		if depth < 1 {
+			return errors.New("max depth must be at least 1")
+		}
+		o.jsonLimits.maxDepth = depth
+		return nil
+	}
+}
+
+func MaxArrayLength(length int) objectValidatorOpt {
+	return func(o *objectValidator) error {
+		o.jsonLimits.maxArrayLength = length
+		return nil
+	}
+}
+
+func MaxObjectKeys(count int) objectValidatorOpt {
+	return func(o *objectValidator) error {
+		o.jsonLimits.maxObjectKeys = count
+		return nil
+	}
+}
+
+// RejectDuplicateKeys reports an error when a key is repeated in an object instead of keeping the last value
+func RejectDuplicateKeys() objectValidatorOpt {
+	return func(o *objectValidator) error {
+		o.jsonLimits.rejectDuplicateKeys = true
+		return nil
+	}
+}
+
+// DisallowTrailingData rejects anything other than white space after the JSON value. A single document is always
+// rejected when followed by another value, this option also applies the check after the closing bracket in ParseStream.
+func DisallowTrailingData() objectValidatorOpt {
+	return func(o *objectValidator) error {
+		o.jsonLimits.disallowTrailingData = true
+		return nil
+	}
+}
+
+func (o *objectValidator) decodeJSON(val []byte) (map[string]interface{}, *parseError) {
+	dec := json.NewDecoder(bytes.NewReader(val))
+	dec.UseNumber()
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, &parseError{message: "unmarshalling JSON value", inner: []error{err}}
+	}
+	if tok != json.Delim('{') {
+		return nil, &parseError{message: "unmarshalling JSON value", inner: []error{errors.New("JSON value is not an object")}}
+	}
+
+	unpacked, perr := o.jsonLimits.readObject(dec, 1, "")
+	if perr != nil {
+		return nil, perr
+	}
+
+	if perr := checkTrailingData(dec); perr != nil {
+		return nil, perr
+	}
+
+	return unpacked, nil
+}
+
+// depthLimit is the configured maximum depth, an unbounded depth would let a deeply nested document exhaust the stack
+func (l *jsonLimits) depthLimit() int {
+	if l.maxDepth > 0 {
+		return l.maxDepth
+	}
+	return DefaultMaxDecodeDepth
+}
+
+func checkTrailingData(dec *json.Decoder) *parseError {
+	_, err := dec.Token()
+	if err == io.EOF {
+		return nil
+	}
+	return JSONTrailingDataError
+}
+
+func (l *jsonLimits) readValue(dec *json.Decoder, depth int, path string) (any, *parseError) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, &parseError{message: "unmarshalling JSON value", inner: []error{err}, path: path}
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		return l.readObject(dec, depth+1, path)
+	case json.Delim('['):
+		return l.readArray(dec, depth+1, path)
+	}
+
+	return tok, nil
+}
+
+// readObject reads the members of an object, the opening brace must already have been consumed
+func (l *jsonLimits) readObject(dec *json.Decoder, depth int, path string) (map[string]interface{}, *parseError) {
+	if depth > l.depthLimit() {
+		return nil, JSONTooDeepError.withPath(path)
+	}
+
+	obj := make(map[string]interface{})
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, &parseError{message: "unmarshalling JSON value", inner: []error{err}, path: path}
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, &parseError{message: "unmarshalling JSON value", inner: []error{errors.New("invalid object key")}, path: path}
+		}
+
+		keyPath := joinPath(path, key)
+		if _, ok := obj[key]; ok && l.rejectDuplicateKeys {
+			return nil, JSONDuplicateKeyError.withPath(keyPath)
+		}
+		if l.maxObjectKeys > 0 && len(obj) >= l.maxObjectKeys {
+			return nil, JSONTooManyKeysError.withPath(path)
+		}
+
+		val, perr := l.readValue(dec, depth, keyPath)
+		if perr != nil {
+			return nil, perr
+		}
+		obj[key] = val
+	}
+
+	// consume the closing brace
+	if _, err := dec.Token(); err != nil {
+		return nil, &parseError{message: "unmarshalling JSON value", inner: []error{err}, path: path}
+	}
+	return obj, nil
+}
+
+func (l *jsonLimits) readArray(dec *json.Decoder, depth int, path string) ([]any, *parseError) {
+	if depth > l.depthLimit() {
+		return nil, JSONTooDeepError.withPath(path)
+	}
+
+	arr := make([]any, 0)
+	for dec.More() {
+		if l.maxArrayLength > 0 && len(arr) >= l.maxArrayLength {
+			return nil, JSONArrayTooLongError.withPath(path)
+		}
+		val, perr := l.readValue(dec, depth, joinPath(path, strconv.Itoa(len(arr))))
+		if perr != nil {
+			return nil, perr
+		}
+		arr = append(arr, val)
+	}
+
+	// consume the closing bracket
+	if _, err := dec.Token(); err != nil {
+		return nil, &parseError{message: "unmarshalling JSON value", inner: []error{err}, path: path}
+	}
+	return arr, nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
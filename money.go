@@ -0,0 +1,183 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"math/big"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// MonetaryAmount is an amount of money stored in the minor unit of the currency e.g. cents for EUR
+type MonetaryAmount struct {
+	MinorUnits int64  `json:"minorUnits"`
+	Currency   string `json:"currency"`
+}
+
+type moneyValidatorOpt = parseOpt[MonetaryAmount]
+
+var InvalidCurrencyError = &parseError{
+	message: "invalid currency code",
+}
+
+var MoneyPrecisionError = &parseError{
+	message: "amount has too many decimal places for the currency",
+}
+
+// Decimal returns the amount in the major unit of the currency e.g. 12.34 for 1234 cents
+func (m MonetaryAmount) Decimal() *big.Rat {
+	units := currencyMinorUnits[m.Currency]
+	return new(big.Rat).SetFrac(big.NewInt(m.MinorUnits), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(units)), nil))
+}
+
+func (m MonetaryAmount) String() string {
+	return m.Currency + " " + m.Decimal().FloatString(currencyMinorUnits[m.Currency])
+}
+
+// Money parses amounts from objects e.g. {"amount": "12.34", "currency": "EUR"} or strings e.g. "EUR 12.34".
+// The amount may not have more decimal places than the currency allows. Number options such as Min and Max
+// are applied to the amount in the major unit of whichever currency was given.
+func Money(opts ...any) genericValidator[MonetaryAmount] {
+	return moneyValidatorFactory(opts...)
+}
+
+func moneyValidatorFactory(opts ...any) validatorWithOpts[MonetaryAmount] {
+	v := validatorFactory[MonetaryAmount](wrapNumericOpts[MonetaryAmount](opts)...)
+	v.setTransformer(func(val any) (any, error) {
+		return coerceToMoney(val)
+	})
+	return v
+}
+
+func coerceToMoney(val any) (MonetaryAmount, error) {
+	var amount any
+	var currency string
+
+	vo := reflect.ValueOf(val)
+	if vo.Kind() == reflect.Ptr {
+		if vo.IsNil() {
+			return MonetaryAmount{}, InvalidValueError
+		}
+		vo = vo.Elem()
+	}
+
+	switch vo.Kind() {
+	case reflect.String:
+		parts := strings.Fields(vo.String())
+		if len(parts) != 2 {
+			return MonetaryAmount{}, InvalidValueError
+		}
+		// allow the currency either side of the amount
+		if _, ok := parseDecimal(parts[0]); ok {
+			amount, currency = parts[0], parts[1]
+		} else {
+			amount, currency = parts[1], parts[0]
+		}
+	case reflect.Map:
+		if vo.Type().Key().Kind() != reflect.String {
+			return MonetaryAmount{}, InvalidValueError
+		}
+		if a := vo.MapIndex(reflect.ValueOf("amount")); a.IsValid() {
+			amount = a.Interface()
+		}
+		if c := vo.MapIndex(reflect.ValueOf("currency")); c.IsValid() {
+			currency, _ = c.Interface().(string)
+		}
+	default:
+		return MonetaryAmount{}, InvalidValueError
+	}
+
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	units, ok := currencyMinorUnits[currency]
+	if !ok {
+		return MonetaryAmount{}, InvalidCurrencyError
+	}
+
+	r, ok := toRat(amount)
+	if !ok {
+		return MonetaryAmount{}, InvalidValueError
+	}
+
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(units)), nil)))
+	if !r.IsInt() {
+		return MonetaryAmount{}, MoneyPrecisionError
+	}
+	if !r.Num().IsInt64() {
+		return MonetaryAmount{}, NumberOutOfRangeError
+	}
+
+	return MonetaryAmount{MinorUnits: r.Num().Int64(), Currency: currency}, nil
+}
+
+// AllowedCurrencies accepts amounts in the listed currencies, case is ignored
+func AllowedCurrencies(codes ...string) moneyValidatorOpt {
+	allowed := make([]string, len(codes))
+	for i, code := range codes {
+		allowed[i] = strings.ToUpper(strings.TrimSpace(code))
+	}
+	return func(val *MonetaryAmount) *parseError {
+		if val == nil {
+			return nil
+		}
+		if !slices.Contains(allowed, strings.ToUpper(val.Currency)) {
+			return &parseError{message: "currency not allowed"}
+		}
+		return nil
+	}
+}
+
+// MinMoney sets a lower limit for amounts in the same currency as the limit e.g. "EUR 1.00",
+// limits in other currencies are ignored so several can be given
+func MinMoney(limit string, message ...string) moneyValidatorOpt {
+	bound, err := coerceToMoney(limit)
+	return func(val *MonetaryAmount) *parseError {
+		if val == nil {
+			return nil
+		}
+		if err != nil {
+			return InvalidNumberBoundError
+		}
+		if val.Currency == bound.Currency && val.MinorUnits < bound.MinorUnits {
+			if len(message) > 0 {
+				return &parseError{message: message[0]}
+			}
+			return &parseError{message: "amount too small"}
+		}
+		return nil
+	}
+}
+
+// MaxMoney sets an upper limit for amounts in the same currency as the limit e.g. "EUR 1000.00"
+func MaxMoney(limit string, message ...string) moneyValidatorOpt {
+	bound, err := coerceToMoney(limit)
+	return func(val *MonetaryAmount) *parseError {
+		if val == nil {
+			return nil
+		}
+		if err != nil {
+			return InvalidNumberBoundError
+		}
+		if val.Currency == bound.Currency && val.MinorUnits > bound.MinorUnits {
+			if len(message) > 0 {
+				return &parseError{message: message[0]}
+			}
+			return &parseError{message: "amount too large"}
+		}
+		return nil
+	}
+}
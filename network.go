@@ -0,0 +1,552 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	InvalidIPError       = &parseError{message: "invalid IP address", code: "invalid_ip"}
+	InvalidPrefixError   = &parseError{message: "invalid CIDR", code: "invalid_cidr"}
+	InvalidHostPortError = &parseError{message: "invalid host and port", code: "invalid_host_port"}
+)
+
+// DefaultHostResolveTimeout is the time allowed to resolve a host name when a resolver is configured
+var DefaultHostResolveTimeout = 2 * time.Second
+
+// ipOpt checks an address or prefix, single addresses are passed as a prefix of their full length
+type ipOpt func(p netip.Prefix) *parseError
+
+// hostOpt checks the host of a URL or HostAndPort
+type hostOpt func(host string) *parseError
+
+// portOpt checks the port of a URL or HostAndPort, URLs without a port use the default port of the scheme
+type portOpt func(port int) *parseError
+
+type netOpt func(c *netConfig)
+
+type netConfig struct {
+	ipChecks    []ipOpt
+	hostChecks  []hostOpt
+	portChecks  []portOpt
+	resolver    HostResolver
+	resolveCtx  context.Context
+	timeout     time.Duration
+	defaultPort int
+}
+
+// HostResolver is satisfied by *net.Resolver, a stub can be used in tests
+type HostResolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// WithHostResolver resolves host names so the IP options also apply to the addresses a name points to. The
+// addresses may change before a request is made so connections should be checked again e.g. with a net.Dialer
+// Control function.
+func WithHostResolver(resolver HostResolver) netOpt {
+	return func(c *netConfig) {
+		c.resolver = resolver
+	}
+}
+
+// WithHostResolveContext sets the parent context of host name lookups e.g. the context of the request being handled
+// when the schema is built per request, a cancelled context fails the lookup
+func WithHostResolveContext(ctx context.Context) netOpt {
+	return func(c *netConfig) {
+		c.resolveCtx = ctx
+	}
+}
+
+func WithHostResolveTimeout(timeout time.Duration) netOpt {
+	return func(c *netConfig) {
+		c.timeout = timeout
+	}
+}
+
+// DefaultPort is used for a HostAndPort given without a port
+func DefaultPort(port uint16) netOpt {
+	return func(c *netConfig) {
+		c.defaultPort = int(port)
+	}
+}
+
+func newNetConfig(opts []any) (*netConfig, []any) {
+	cfg := &netConfig{timeout: DefaultHostResolveTimeout, resolveCtx: context.Background()}
+	remaining := make([]any, 0, len(opts))
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case netOpt:
+			opt(cfg)
+		case ipOpt:
+			cfg.ipChecks = append(cfg.ipChecks, opt)
+		case hostOpt:
+			cfg.hostChecks = append(cfg.hostChecks, opt)
+		case portOpt:
+			cfg.portChecks = append(cfg.portChecks, opt)
+		default:
+			remaining = append(remaining, opt)
+		}
+	}
+	return cfg, remaining
+}
+
+func (c *netConfig) checkPrefix(p netip.Prefix) *parseError {
+	// IPv4 mapped addresses e.g. ::ffff:127.0.0.1 are checked as IPv4
+	if p.Addr().Is4In6() {
+		bits := p.Bits() - 96
+		if bits < 0 {
+			bits = 0
+		}
+		p = netip.PrefixFrom(p.Addr().Unmap(), bits)
+	}
+	for _, check := range c.ipChecks {
+		if err := check(p.Masked()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *netConfig) checkAddr(addr netip.Addr) *parseError {
+	return c.checkPrefix(netip.PrefixFrom(addr.WithZone(""), addr.BitLen()))
+}
+
+// checkEndpoint applies the host and port options, then the IP options to an IP host or to the addresses of a name
+func (c *netConfig) checkEndpoint(host string, port int) *parseError {
+	for _, check := range c.hostChecks {
+		if err := check(host); err != nil {
+			return err
+		}
+	}
+	if port <= 0 && len(c.portChecks) > 0 {
+		// e.g. a scheme with no well known port, skipping the checks would let any port through
+		return &parseError{message: "port could not be determined", code: "port_unknown"}
+	}
+	for _, check := range c.portChecks {
+		if err := check(port); err != nil {
+			return err
+		}
+	}
+	if len(c.ipChecks) == 0 {
+		return nil
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return c.checkAddr(addr)
+	}
+	addrs, ok := specialHostAddrs(host)
+	if !ok && c.resolver == nil {
+		// the IP options can't be applied to a name without its addresses so it is rejected rather than let through
+		return &parseError{message: "host name can't be checked without a resolver", code: "resolver_required", params: map[string]any{"host": host}}
+	}
+	if !ok {
+		ctx, cancel := context.WithTimeout(c.resolveCtx, c.timeout)
+		defer cancel()
+		resolved, err := c.resolver.LookupNetIP(ctx, "ip", host)
+		if err != nil || len(resolved) == 0 {
+			return &parseError{message: "host could not be resolved", code: "host_unresolved", params: map[string]any{"host": host}}
+		}
+		addrs = resolved
+	}
+	for _, addr := range addrs {
+		if err := c.checkAddr(addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// specialHostAddrs returns the addresses of names which never reach the public internet
+func specialHostAddrs(host string) ([]netip.Addr, bool) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	switch {
+	case host == "localhost" || strings.HasSuffix(host, ".localhost"):
+		return []netip.Addr{netip.IPv6Loopback(), netip.AddrFrom4([4]byte{127, 0, 0, 1})}, true
+	case slices.Contains(metadataHosts, host):
+		return []netip.Addr{netip.AddrFrom4([4]byte{169, 254, 169, 254})}, true
+	}
+	return nil, false
+}
+
+// metadataHosts are the names of cloud instance metadata services
+var metadataHosts = []string{"metadata.google.internal", "metadata.goog", "metadata", "instance-data", "instance-data.ec2.internal"}
+
+// internalPrefixes are the ranges PublicAddressOnly rejects, including the metadata services at 169.254.169.254,
+// fd00:ec2::254 and 100.100.100.200 and the IPv6 ranges which embed IPv4 addresses i.e. IPv4 compatible, NAT64,
+// 6to4 and Teredo
+var internalPrefixes = func() []netip.Prefix {
+	ranges := []string{
+		"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16", "172.16.0.0/12",
+		"192.0.0.0/24", "192.168.0.0/16", "198.18.0.0/15", "224.0.0.0/4", "240.0.0.0/4",
+		"::/96", "64:ff9b::/96", "64:ff9b:1::/48", "100::/64", "2001::/32", "2002::/16", "fc00::/7", "fe80::/10", "ff00::/8",
+	}
+	prefixes := make([]netip.Prefix, len(ranges))
+	for i, r := range ranges {
+		prefixes[i] = netip.MustParsePrefix(r)
+	}
+	return prefixes
+}()
+
+// PublicAddressOnly guards against server side request forgery by rejecting loopback, private, link local,
+// multicast, reserved and cloud metadata addresses. Host names are rejected unless a resolver is configured so
+// their addresses can be checked.
+func PublicAddressOnly(message ...string) ipOpt {
+	return func(p netip.Prefix) *parseError {
+		for _, internal := range internalPrefixes {
+			if internal.Overlaps(p) {
+				return netError("internal_address", "address is not public", map[string]any{"address": p.Addr().String()}, message...)
+			}
+		}
+		return nil
+	}
+}
+
+// privatePrefixes are the private ranges in RFC 1918 and RFC 4193
+var privatePrefixes = []netip.Prefix{
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("fc00::/7"),
+}
+
+// PrivateIPAllowed set to false rejects the private ranges in RFC 1918 and RFC 4193, a prefix is rejected if it
+// overlaps one of them e.g. 0.0.0.0/0
+func PrivateIPAllowed(allowed bool, message ...string) ipOpt {
+	return func(p netip.Prefix) *parseError {
+		if allowed {
+			return nil
+		}
+		for _, private := range privatePrefixes {
+			if private.Overlaps(p) {
+				return netError("private_ip", "private IP address not allowed", map[string]any{"address": p.Addr().String()}, message...)
+			}
+		}
+		return nil
+	}
+}
+
+// InPrefix accepts addresses and prefixes which lie within one of the prefixes e.g. InPrefix("10.0.0.0/8")
+func InPrefix(prefixes ...string) ipOpt {
+	parsed := make([]netip.Prefix, 0, len(prefixes))
+	var parseErr error
+	for _, s := range prefixes {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			parseErr = err
+		}
+		parsed = append(parsed, p.Masked())
+	}
+	return func(p netip.Prefix) *parseError {
+		if parseErr != nil {
+			return &parseError{message: "invalid prefix", inner: []error{parseErr}}
+		}
+		for _, outer := range parsed {
+			if outer.Bits() <= p.Bits() && outer.Contains(p.Addr()) {
+				return nil
+			}
+		}
+		return &parseError{message: "address not in allowed range", code: "ip_not_in_prefix", params: map[string]any{"prefixes": prefixes}}
+	}
+}
+
+// HostAllowlist accepts the listed hosts, an entry such as *.example.com matches any subdomain
+func HostAllowlist(hosts ...string) hostOpt {
+	return func(host string) *parseError {
+		host = strings.TrimSuffix(strings.ToLower(host), ".")
+		for _, allowed := range hosts {
+			allowed = strings.ToLower(allowed)
+			if suffix, ok := strings.CutPrefix(allowed, "*"); ok {
+				if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+					return nil
+				}
+				continue
+			}
+			if host == allowed {
+				return nil
+			}
+		}
+		return &parseError{message: "host not allowed", code: "host_not_allowed", params: map[string]any{"host": host}}
+	}
+}
+
+func PortRange(min, max int, message ...string) portOpt {
+	return func(port int) *parseError {
+		if port < min || port > max {
+			return netError("port_out_of_range", "port out of range", map[string]any{"min": min, "max": max}, message...)
+		}
+		return nil
+	}
+}
+
+func netError(code, defaultMessage string, params map[string]any, message ...string) *parseError {
+	if len(message) > 0 {
+		return &parseError{message: message[0], code: code, params: params}
+	}
+	return &parseError{message: defaultMessage, code: code, params: params}
+}
+
+// IPAddr parses IPv4 and IPv6 addresses from strings and net.IP
+func IPAddr(opts ...any) genericValidator[netip.Addr] {
+	return ipAddrValidatorFactory(opts...)
+}
+
+func ipAddrValidatorFactory(opts ...any) validatorWithOpts[netip.Addr] {
+	cfg, remaining := newNetConfig(opts)
+	remaining = append(remaining, parseOpt[netip.Addr](func(val *netip.Addr) *parseError {
+		if val == nil {
+			return nil
+		}
+		return cfg.checkAddr(*val)
+	}))
+	v := validatorFactory[netip.Addr](remaining...)
+	if !v.hasTransformer() {
+		v.setTransformer(func(val any) (any, error) {
+			return coerceToAddr(val)
+		})
+	}
+	return v
+}
+
+func coerceToAddr(val any) (netip.Addr, error) {
+	vo := reflect.Indirect(reflect.ValueOf(val))
+	if !vo.IsValid() {
+		return netip.Addr{}, InvalidTypeError
+	}
+	switch {
+	case vo.Kind() == reflect.String:
+		addr, err := netip.ParseAddr(strings.TrimSpace(vo.String()))
+		if err != nil {
+			return netip.Addr{}, InvalidIPError
+		}
+		return addr, nil
+	case vo.Kind() == reflect.Slice && vo.Type().Elem().Kind() == reflect.Uint8:
+		// net.IP holds IPv4 addresses in 16 bytes
+		addr, ok := netip.AddrFromSlice(vo.Bytes())
+		if !ok {
+			return netip.Addr{}, InvalidIPError
+		}
+		return addr.Unmap(), nil
+	}
+	return netip.Addr{}, InvalidTypeError
+}
+
+// IPPrefix parses prefixes in CIDR notation e.g. 10.0.0.0/8, the IP options apply to the whole prefix
+func IPPrefix(opts ...any) genericValidator[netip.Prefix] {
+	return ipPrefixValidatorFactory(opts...)
+}
+
+func ipPrefixValidatorFactory(opts ...any) validatorWithOpts[netip.Prefix] {
+	cfg, remaining := newNetConfig(opts)
+	remaining = append(remaining, parseOpt[netip.Prefix](func(val *netip.Prefix) *parseError {
+		if val == nil {
+			return nil
+		}
+		return cfg.checkPrefix(*val)
+	}))
+	v := validatorFactory[netip.Prefix](remaining...)
+	if !v.hasTransformer() {
+		v.setTransformer(func(val any) (any, error) {
+			return coerceToPrefix(val)
+		})
+	}
+	return v
+}
+
+func coerceToPrefix(val any) (netip.Prefix, error) {
+	vo := reflect.Indirect(reflect.ValueOf(val))
+	if !vo.IsValid() {
+		return netip.Prefix{}, InvalidTypeError
+	}
+	if ipNet, ok := vo.Interface().(net.IPNet); ok {
+		return coerceToPrefix(ipNet.String())
+	}
+	if vo.Kind() != reflect.String {
+		return netip.Prefix{}, InvalidTypeError
+	}
+	p, err := netip.ParsePrefix(strings.TrimSpace(vo.String()))
+	if err != nil {
+		return netip.Prefix{}, InvalidPrefixError
+	}
+	return p, nil
+}
+
+// ParsedURL parses absolute URLs with a host e.g. for webhooks, the host must be a valid host name or IP address
+func ParsedURL(opts ...any) genericValidator[*url.URL] {
+	return urlValidatorFactory(opts...)
+}
+
+type urlValidatorOpt = parseOpt[*url.URL]
+
+var defaultPorts = map[string]int{"http": 80, "https": 443, "ws": 80, "wss": 443, "ftp": 21}
+
+func urlValidatorFactory(opts ...any) validatorWithOpts[*url.URL] {
+	cfg, remaining := newNetConfig(opts)
+	remaining = append(remaining, urlValidatorOpt(func(val **url.URL) *parseError {
+		if val == nil || *val == nil {
+			return nil
+		}
+		u := *val
+		port := defaultPorts[strings.ToLower(u.Scheme)]
+		if u.Port() > "" {
+			port, _ = strconv.Atoi(u.Port())
+		}
+		return cfg.checkEndpoint(u.Hostname(), port)
+	}))
+	v := validatorFactory[*url.URL](remaining...)
+	if !v.hasTransformer() {
+		v.setTransformer(func(val any) (any, error) {
+			return coerceToURL(val)
+		})
+	}
+	return v
+}
+
+var InvalidURLError = &parseError{message: "invalid URL", code: "invalid_url"}
+
+func coerceToURL(val any) (*url.URL, error) {
+	var u *url.URL
+	switch val := val.(type) {
+	case *url.URL:
+		if val == nil {
+			return nil, InvalidTypeError
+		}
+		copied := *val
+		u = &copied
+	case url.URL:
+		u = &val
+	case string:
+		parsed, err := url.Parse(strings.TrimSpace(val))
+		if err != nil {
+			return nil, InvalidURLError
+		}
+		u = parsed
+	default:
+		return nil, InvalidTypeError
+	}
+
+	if u.Scheme == "" || u.Host == "" || !isHost(u.Hostname()) {
+		return nil, InvalidURLError
+	}
+	if p := u.Port(); p > "" {
+		if n, err := strconv.Atoi(p); err != nil || n < 1 || n > 65535 {
+			return nil, InvalidURLError
+		}
+	}
+	return u, nil
+}
+
+// isHost accepts host names and IP addresses, other numeric forms e.g. 2130706433 or 0x7f000001 are rejected
+// because inet_aton and the clients which use it treat them as IPv4 addresses
+func isHost(host string) bool {
+	if _, err := netip.ParseAddr(host); err == nil {
+		return true
+	}
+	host = strings.TrimSuffix(host, ".")
+	labels := strings.Split(host, ".")
+	if isIPv4Number(labels[len(labels)-1]) {
+		return false
+	}
+	return isHostname(host)
+}
+
+// isIPv4Number reports whether inet_aton would read the label as a number i.e. decimal, octal with a leading 0
+// or hex with a leading 0x
+func isIPv4Number(label string) bool {
+	digits := "0123456789"
+	if hex, ok := strings.CutPrefix(strings.ToLower(label), "0x"); ok {
+		label, digits = hex, "0123456789abcdef"
+	}
+	return strings.Trim(label, digits) == ""
+}
+
+// Scheme accepts URLs with one of the schemes, case is ignored
+func Scheme(schemes ...string) urlValidatorOpt {
+	return func(val **url.URL) *parseError {
+		if val == nil || *val == nil {
+			return nil
+		}
+		for _, scheme := range schemes {
+			if strings.EqualFold((*val).Scheme, scheme) {
+				return nil
+			}
+		}
+		return &parseError{message: "URL scheme not allowed", code: "invalid_scheme", params: map[string]any{"schemes": schemes}}
+	}
+}
+
+// HostAndPort is a network endpoint e.g. db.example.com:5432
+type HostAndPort struct {
+	Host string `json:"host"`
+	Port uint16 `json:"port"`
+}
+
+func (h HostAndPort) String() string {
+	return net.JoinHostPort(h.Host, strconv.Itoa(int(h.Port)))
+}
+
+// HostPort parses endpoints from strings e.g. example.com:443 or [::1]:8080, use DefaultPort to allow the port
+// to be left out
+func HostPort(opts ...any) genericValidator[HostAndPort] {
+	return hostPortValidatorFactory(opts...)
+}
+
+func hostPortValidatorFactory(opts ...any) validatorWithOpts[HostAndPort] {
+	cfg, remaining := newNetConfig(opts)
+	remaining = append(remaining, parseOpt[HostAndPort](func(val *HostAndPort) *parseError {
+		if val == nil {
+			return nil
+		}
+		return cfg.checkEndpoint(val.Host, int(val.Port))
+	}))
+	v := validatorFactory[HostAndPort](remaining...)
+	if !v.hasTransformer() {
+		v.setTransformer(func(val any) (any, error) {
+			return cfg.coerceToHostPort(val)
+		})
+	}
+	return v
+}
+
+func (c *netConfig) coerceToHostPort(val any) (HostAndPort, error) {
+	vo := reflect.Indirect(reflect.ValueOf(val))
+	if !vo.IsValid() || vo.Kind() != reflect.String {
+		return HostAndPort{}, InvalidTypeError
+	}
+	s := strings.TrimSpace(vo.String())
+
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		if c.defaultPort == 0 {
+			return HostAndPort{}, InvalidHostPortError
+		}
+		host, port = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]"), strconv.Itoa(c.defaultPort)
+	}
+	n, err := strconv.ParseUint(port, 10, 16)
+	if err != nil || n == 0 || !isHost(host) {
+		return HostAndPort{}, InvalidHostPortError
+	}
+	return HostAndPort{Host: host, Port: uint16(n)}, nil
+}
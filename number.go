@@ -1,7 +1,9 @@
 package ursa
 
 import (
+	"encoding/json"
 	"math"
+	"math/big"
 	"reflect"
 	"strconv"
 	"strings"
@@ -29,39 +31,65 @@ type number interface {
 	constraints.Integer | constraints.Float
 }
 
-type numberValidatorOpt func(val float64) *parseError
+// exactNumber is anything which can be converted to a big.Rat without loss e.g. a bound passed to Min
+type exactNumber interface {
+	constraints.Integer | constraints.Float | ~string | *big.Int | *big.Rat
+}
+
+// numberValidatorOpt receives the value as an exact rational so that checks on large integers and decimals are not affected by float rounding
+type numberValidatorOpt func(val *big.Rat) *parseError
+
+var NonFiniteNumberError = &parseError{
+	message: "number is not finite",
+}
+
+var InvalidNumberBoundError = &parseError{
+	message: "invalid number bound",
+}
 
-// to make things simpler all numbers are coerced to float64 before invoking the validator
 func numericOptWrapper[T any](fn numberValidatorOpt) parseOpt[T] {
 	return func(val *T) *parseError {
 		if val == nil {
 			return nil
 		}
-		var zero float64
-		zeroType := reflect.TypeOf(zero)
-		v := *val
-		vo := reflect.ValueOf(v)
-		if reflect.TypeOf(v).ConvertibleTo(zeroType) {
-			n := vo.Convert(zeroType).Interface().(float64)
-			return fn(n)
+		if isNonFinite(*val) {
+			// non-finite values are reported once by Finite or by the check added in wrapNumericOpts
+			return nil
+		}
+		r, ok := toRat(*val)
+		if !ok {
+			return InvalidTypeError
 		}
-		return InvalidTypeError
+		return fn(r)
 	}
 }
 
 func numericValidatorFactory[T number](opts ...any) validatorWithOpts[T] {
-	wrappedOpts := make([]any, len(opts))
-	for i, opt := range opts {
-		if fn, ok := opt.(numberValidatorOpt); ok {
-			wrappedOpts[i] = numericOptWrapper[T](fn)
-		} else {
-			wrappedOpts[i] = opt
+	return validatorFactory[T](wrapNumericOpts[T](opts)...)
+}
+
+func wrapNumericOpts[T any](opts []any) []any {
+	wrappedOpts := make([]any, 0, len(opts)+1)
+	hasNumberOpts, hasFinite := false, false
+	for _, opt := range opts {
+		switch fn := opt.(type) {
+		case numberValidatorOpt:
+			wrappedOpts = append(wrappedOpts, numericOptWrapper[T](fn))
+			hasNumberOpts = true
+		case finiteOpt:
+			wrappedOpts = append(wrappedOpts, floatOptWrapper[T](floatValidatorOpt(fn)))
+			hasFinite = true
+		case floatValidatorOpt:
+			wrappedOpts = append(wrappedOpts, floatOptWrapper[T](fn))
+		default:
+			wrappedOpts = append(wrappedOpts, opt)
 		}
 	}
-
-	v := validatorFactory[T](wrappedOpts...)
-
-	return v
+	// NaN and infinity can't be compared so they fail the numeric checks, unless Finite reports them
+	if hasNumberOpts && !hasFinite {
+		wrappedOpts = append([]any{floatOptWrapper[T](floatValidatorOpt(Finite()))}, wrappedOpts...)
+	}
+	return wrappedOpts
 }
 
 func Int(opts ...any) genericValidator[int] {
@@ -104,33 +132,144 @@ func Float64(opts ...any) genericValidator[float64] {
 	return numericValidatorFactory[float64](opts...)
 }
 
-func Min(min float64, message ...string) numberValidatorOpt {
-	return func(val float64) *parseError {
-		if val < min {
+func Min[N exactNumber](min N, message ...string) numberValidatorOpt {
+	bound, ok := toRat(min)
+	return func(val *big.Rat) *parseError {
+		if !ok {
+			return InvalidNumberBoundError
+		}
+		if val.Cmp(bound) < 0 {
+			params := map[string]any{"min": formatRat(bound)}
 			if len(message) > 0 {
-				return &parseError{message: message[0]}
+				return &parseError{message: message[0], params: params}
 			}
-			return &parseError{message: "number too small"}
+			return &parseError{message: "number too small", params: params}
 		}
 		return nil
 	}
 }
 
-func Max(max float64, message ...string) numberValidatorOpt {
+func Max[N exactNumber](max N, message ...string) numberValidatorOpt {
+	bound, ok := toRat(max)
+	return func(val *big.Rat) *parseError {
+		if !ok {
+			return InvalidNumberBoundError
+		}
+		if val.Cmp(bound) > 0 {
+			params := map[string]any{"max": formatRat(bound)}
+			if len(message) > 0 {
+				return &parseError{message: message[0], params: params}
+			}
+			return &parseError{message: "number too large", params: params}
+		}
+		return nil
+	}
+}
+
+// GreaterThan is an exclusive lower bound
+func GreaterThan[N exactNumber](min N, message ...string) numberValidatorOpt {
+	bound, ok := toRat(min)
+	return func(val *big.Rat) *parseError {
+		if !ok {
+			return InvalidNumberBoundError
+		}
+		if val.Cmp(bound) <= 0 {
+			params := map[string]any{"greaterThan": formatRat(bound)}
+			if len(message) > 0 {
+				return &parseError{message: message[0], params: params}
+			}
+			return &parseError{message: "number too small", params: params}
+		}
+		return nil
+	}
+}
+
+// LessThan is an exclusive upper bound
+func LessThan[N exactNumber](max N, message ...string) numberValidatorOpt {
+	bound, ok := toRat(max)
+	return func(val *big.Rat) *parseError {
+		if !ok {
+			return InvalidNumberBoundError
+		}
+		if val.Cmp(bound) >= 0 {
+			params := map[string]any{"lessThan": formatRat(bound)}
+			if len(message) > 0 {
+				return &parseError{message: message[0], params: params}
+			}
+			return &parseError{message: "number too large", params: params}
+		}
+		return nil
+	}
+}
+
+func Positive(message ...string) numberValidatorOpt {
+	return GreaterThan(0, message...)
+}
+
+func Negative(message ...string) numberValidatorOpt {
+	return LessThan(0, message...)
+}
+
+func NonNegative(message ...string) numberValidatorOpt {
+	return Min(0, message...)
+}
+
+// OneOf restricts the value to a set of numbers e.g. OneOf(1, 2, 5, 10)
+func OneOf[N exactNumber](values ...N) numberValidatorOpt {
+	allowed := make([]*big.Rat, 0, len(values))
+	formatted := make([]string, 0, len(values))
+	for _, v := range values {
+		if r, ok := toRat(v); ok {
+			allowed = append(allowed, r)
+			formatted = append(formatted, formatRat(r))
+		}
+	}
+	return func(val *big.Rat) *parseError {
+		for _, r := range allowed {
+			if val.Cmp(r) == 0 {
+				return nil
+			}
+		}
+		return &parseError{message: "number not found in allowed values", params: map[string]any{"values": formatted}}
+	}
+}
+
+// floatValidatorOpt receives the value as a float64 so that NaN and infinity can be inspected
+type floatValidatorOpt func(val float64) *parseError
+
+// finiteOpt is a floatValidatorOpt which replaces the non-finite check of the numeric options
+type finiteOpt floatValidatorOpt
+
+// Finite rejects NaN and infinite values
+func Finite(message ...string) finiteOpt {
 	return func(val float64) *parseError {
-		if val > max {
+		if math.IsNaN(val) || math.IsInf(val, 0) {
 			if len(message) > 0 {
 				return &parseError{message: message[0]}
 			}
-			return &parseError{message: "number too large"}
+			return NonFiniteNumberError
+		}
+		return nil
+	}
+}
+
+func floatOptWrapper[T any](fn floatValidatorOpt) parseOpt[T] {
+	return func(val *T) *parseError {
+		if val == nil {
+			return nil
+		}
+		vo := reflect.ValueOf(*val)
+		switch vo.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return fn(vo.Float())
 		}
 		return nil
 	}
 }
 
 func NonZero(message ...string) numberValidatorOpt {
-	return func(val float64) *parseError {
-		if val == 0 {
+	return func(val *big.Rat) *parseError {
+		if val.Sign() == 0 {
 			if len(message) > 0 {
 				return &parseError{message: message[0]}
 			}
@@ -141,8 +280,8 @@ func NonZero(message ...string) numberValidatorOpt {
 }
 
 func MustBeInteger(message ...string) numberValidatorOpt {
-	return func(val float64) *parseError {
-		if val != math.Floor(val) {
+	return func(val *big.Rat) *parseError {
+		if !val.IsInt() {
 			if len(message) > 0 {
 				return &parseError{message: message[0]}
 			}
@@ -152,6 +291,157 @@ func MustBeInteger(message ...string) numberValidatorOpt {
 	}
 }
 
+func MultipleOf[N exactNumber](step N, message ...string) numberValidatorOpt {
+	bound, ok := toRat(step)
+	return func(val *big.Rat) *parseError {
+		if !ok || bound.Sign() == 0 {
+			return InvalidNumberBoundError
+		}
+		if !new(big.Rat).Quo(val, bound).IsInt() {
+			params := map[string]any{"step": formatRat(bound)}
+			if len(message) > 0 {
+				return &parseError{message: message[0], params: params}
+			}
+			return &parseError{message: "number is not a multiple of " + formatRat(bound), params: params}
+		}
+		return nil
+	}
+}
+
+// MaxDecimalPlaces limits the number of digits after the decimal point
+func MaxDecimalPlaces(places int, message ...string) numberValidatorOpt {
+	return func(val *big.Rat) *parseError {
+		if n, ok := decimalPlaces(val); !ok || n > places {
+			params := map[string]any{"places": places}
+			if len(message) > 0 {
+				return &parseError{message: message[0], params: params}
+			}
+			return &parseError{message: "number has too many decimal places", params: params}
+		}
+		return nil
+	}
+}
+
+// MaxDigits limits the total number of digits before and after the decimal point e.g. 123.45 has 5 digits
+func MaxDigits(precision int, message ...string) numberValidatorOpt {
+	return func(val *big.Rat) *parseError {
+		places, ok := decimalPlaces(val)
+		if ok {
+			intPart := new(big.Int).Quo(val.Num(), val.Denom())
+			intDigits := 0
+			if intPart.Sign() != 0 {
+				intDigits = len(new(big.Int).Abs(intPart).String())
+			}
+			ok = intDigits+places <= precision
+		}
+		if !ok {
+			params := map[string]any{"digits": precision}
+			if len(message) > 0 {
+				return &parseError{message: message[0], params: params}
+			}
+			return &parseError{message: "number has too many digits", params: params}
+		}
+		return nil
+	}
+}
+
+// decimalPlaces returns the number of digits needed after the decimal point, false if the value is a recurring decimal e.g. 1/3
+func decimalPlaces(val *big.Rat) (int, bool) {
+	den := new(big.Int).Set(val.Denom())
+	twos, fives := 0, 0
+	two, five := big.NewInt(2), big.NewInt(5)
+	rem := new(big.Int)
+	for {
+		q, r := new(big.Int).QuoRem(den, two, rem)
+		if r.Sign() != 0 {
+			break
+		}
+		den = q
+		twos++
+	}
+	for {
+		q, r := new(big.Int).QuoRem(den, five, rem)
+		if r.Sign() != 0 {
+			break
+		}
+		den = q
+		fives++
+	}
+	if den.Cmp(big.NewInt(1)) != 0 {
+		return 0, false
+	}
+	return max(twos, fives), true
+}
+
+// formatRat formats the value as a decimal when it has a finite representation
+func formatRat(val *big.Rat) string {
+	if places, ok := decimalPlaces(val); ok {
+		return val.FloatString(places)
+	}
+	return val.RatString()
+}
+
+func isNonFinite(val any) bool {
+	vo := reflect.ValueOf(val)
+	switch vo.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return math.IsNaN(vo.Float()) || math.IsInf(vo.Float(), 0)
+	}
+	return false
+}
+
+// toRat converts a number to a big.Rat without loss, floats are converted using their shortest decimal representation
+// so that e.g. 0.1 is treated as exactly one tenth
+func toRat(val any) (*big.Rat, bool) {
+	switch v := val.(type) {
+	case *big.Rat:
+		if v == nil {
+			return nil, false
+		}
+		return new(big.Rat).Set(v), true
+	case *big.Int:
+		if v == nil {
+			return nil, false
+		}
+		return new(big.Rat).SetInt(v), true
+	case json.Number:
+		return parseDecimal(string(v))
+	case MonetaryAmount:
+		return v.Decimal(), true
+	}
+
+	vo := reflect.ValueOf(val)
+	switch vo.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Rat).SetInt64(vo.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(vo.Uint())), true
+	case reflect.Float32, reflect.Float64:
+		f := vo.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, false
+		}
+		return parseDecimal(strconv.FormatFloat(f, 'g', -1, vo.Type().Bits()))
+	case reflect.String:
+		return parseDecimal(vo.String())
+	case reflect.Ptr:
+		if vo.IsNil() {
+			return nil, false
+		}
+		return toRat(vo.Elem().Interface())
+	}
+	return nil, false
+}
+
+// parseDecimal parses a decimal string e.g. "-12.50" or "1e-3", fractions such as "1/3" are not accepted
+func parseDecimal(val string) (*big.Rat, bool) {
+	val = strings.TrimSpace(val)
+	if val == "" || strings.ContainsAny(val, "/xXoObB_") {
+		return nil, false
+	}
+	return new(big.Rat).SetString(val)
+}
+
 func coerceToNumber[T number](val any) (T, error) {
 	vo := reflect.ValueOf(val)
 	if vo.Kind() == reflect.Ptr {
@@ -168,11 +458,3 @@ func coerceToNumber[T number](val any) (T, error) {
 		return T(floatVal), nil
 	}
 }
-
-func coerceToBool(val string) (bool, error) {
-	if strings.ToLower(val) == "on" {
-		return true, nil
-	} else {
-		return strconv.ParseBool(val)
-	}
-}
@@ -0,0 +1,315 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// numberFormat describes how numbers are written in a locale
+type numberFormat struct {
+	decimal rune
+	group   []rune
+}
+
+var spaceGroups = []rune{' ', '\u00a0', '\u202f'}
+
+var (
+	pointDecimal = &numberFormat{decimal: '.', group: []rune{','}}
+	commaDecimal = &numberFormat{decimal: ',', group: []rune{'.'}}
+	spaceGrouped = &numberFormat{decimal: ',', group: spaceGroups}
+	swissGrouped = &numberFormat{decimal: '.', group: []rune{'\'', '’'}}
+)
+
+// numberFormats is keyed by language or language and region, the language is used when the region isn't listed
+var numberFormats = map[string]*numberFormat{
+	"en": pointDecimal, "hi": pointDecimal, "ja": pointDecimal, "zh": pointDecimal, "ko": pointDecimal,
+	"he": pointDecimal, "th": pointDecimal, "ms": pointDecimal, "es-mx": pointDecimal,
+
+	"de": commaDecimal, "es": commaDecimal, "it": commaDecimal, "nl": commaDecimal, "pt": commaDecimal,
+	"da": commaDecimal, "id": commaDecimal, "tr": commaDecimal, "el": commaDecimal, "ro": commaDecimal,
+	"hr": commaDecimal, "sl": commaDecimal, "sr": commaDecimal, "vi": commaDecimal,
+
+	"fr": spaceGrouped, "ru": spaceGrouped, "pl": spaceGrouped, "cs": spaceGrouped, "sk": spaceGrouped,
+	"sv": spaceGrouped, "nb": spaceGrouped, "no": spaceGrouped, "fi": spaceGrouped, "uk": spaceGrouped,
+	"hu": spaceGrouped, "bg": spaceGrouped, "lt": spaceGrouped, "lv": spaceGrouped, "et": spaceGrouped,
+	"pt-pt": spaceGrouped,
+
+	"de-ch": swissGrouped, "fr-ch": swissGrouped, "it-ch": swissGrouped, "de-li": swissGrouped,
+}
+
+func lookupNumberFormat(locale string) (*numberFormat, bool) {
+	locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if f, ok := numberFormats[locale]; ok {
+		return f, true
+	}
+	lang, _, _ := strings.Cut(locale, "-")
+	f, ok := numberFormats[lang]
+	return f, ok
+}
+
+// WithNumberFormat parses numeric fields written in the style of a locale e.g. "1.234,56" for "de"
+func WithNumberFormat(locale string) objectValidatorOpt {
+	return func(o *objectValidator) error {
+		f, ok := lookupNumberFormat(locale)
+		if !ok {
+			return errors.New("unsupported number format locale: " + locale)
+		}
+		o.numberFormat = f
+		return nil
+	}
+}
+
+// DetectNumberFormat uses the Accept-Language header of a request to choose the number format,
+// WithNumberFormat is used if none of the languages are recognised
+func DetectNumberFormat() objectValidatorOpt {
+	return func(o *objectValidator) error {
+		o.detectNumberFormat = true
+		return nil
+	}
+}
+
+func (o *objectValidator) requestNumberFormat(req *http.Request) *numberFormat {
+	if o.detectNumberFormat {
+		if f, ok := acceptedNumberFormat(req.Header.Get("Accept-Language")); ok {
+			return f
+		}
+	}
+	return o.numberFormat
+}
+
+// acceptedNumberFormat returns the format of the most preferred language which is recognised
+func acceptedNumberFormat(header string) (*numberFormat, bool) {
+	type preference struct {
+		tag    string
+		weight float64
+	}
+	prefs := make([]preference, 0)
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		pref := preference{tag: strings.TrimSpace(tag), weight: 1}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if w, err := strconv.ParseFloat(q, 64); err == nil {
+				pref.weight = w
+			}
+		}
+		if pref.tag > "" && pref.weight > 0 {
+			prefs = append(prefs, pref)
+		}
+	}
+	slices.SortStableFunc(prefs, func(a, b preference) int {
+		switch {
+		case a.weight > b.weight:
+			return -1
+		case a.weight < b.weight:
+			return 1
+		}
+		return 0
+	})
+
+	for _, pref := range prefs {
+		if f, ok := lookupNumberFormat(pref.tag); ok {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+var (
+	bigRatType = reflect.TypeOf((*big.Rat)(nil))
+	bigIntType = reflect.TypeOf((*big.Int)(nil))
+)
+
+func isNumericType(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return t == bigRatType || t == bigIntType
+}
+
+var InvalidNumberFormatError = &parseError{
+	message: "number is not in the expected format",
+	code:    "invalid_number_format",
+}
+
+func isIntegerType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return t == bigIntType
+}
+
+// normalizeValue rewrites a localised numeric string so it can be parsed into the type, anything else is returned
+// unchanged, a fractional value e.g. "12 %" for an integer type is an error rather than being truncated
+func (f *numberFormat) normalizeValue(val any, t reflect.Type) (any, *parseError) {
+	s, ok := val.(string)
+	if !ok || strings.TrimSpace(s) == "" {
+		return val, nil
+	}
+	n, ok := f.normalize(s)
+	if !ok {
+		// plain numbers e.g. "1e3" are still accepted
+		if _, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			return val, nil
+		}
+		return nil, InvalidNumberFormatError
+	}
+	if isIntegerType(t) {
+		if r, ok := new(big.Rat).SetString(n); !ok || !r.IsInt() {
+			return nil, InexactNumberError
+		}
+	}
+	return n, nil
+}
+
+// normalize converts e.g. "1.234,56 €" or "12 %" into a plain decimal string such as "1234.56" or "0.12"
+func (f *numberFormat) normalize(s string) (string, bool) {
+	s = strings.TrimFunc(s, unicode.IsSpace)
+
+	percent := false
+	if trimmed, ok := cutPercent(s); ok {
+		s = trimmed
+		percent = true
+	}
+
+	s, negative := cutSign(trimCurrency(s))
+	s = trimCurrency(s)
+	if !negative {
+		// the sign may also follow the currency symbol e.g. "$-5.00"
+		s, negative = cutSign(s)
+	}
+
+	out := new(strings.Builder)
+	if negative {
+		out.WriteByte('-')
+	}
+
+	// groups holds the number of digits between separators in the integer part so misplaced separators are rejected
+	groups := []int{0}
+	seenDecimal := false
+	digits := 0
+	for _, r := range s {
+		if d, ok := digitValue(r); ok {
+			out.WriteByte(byte('0' + d))
+			digits++
+			if !seenDecimal {
+				groups[len(groups)-1]++
+			}
+			continue
+		}
+		switch {
+		case r == f.decimal && !seenDecimal:
+			out.WriteByte('.')
+			seenDecimal = true
+		case slices.Contains(f.group, r) && !seenDecimal && groups[len(groups)-1] > 0:
+			groups = append(groups, 0)
+		default:
+			return "", false
+		}
+	}
+	if digits == 0 || !validGrouping(groups) {
+		return "", false
+	}
+
+	if !percent {
+		return out.String(), true
+	}
+	r, ok := new(big.Rat).SetString(out.String())
+	if !ok {
+		return "", false
+	}
+	return formatRat(r.Quo(r, big.NewRat(100, 1))), true
+}
+
+// validGrouping accepts thousands e.g. 1,234,567 and the Indian lakh and crore style e.g. 12,34,567
+func validGrouping(groups []int) bool {
+	if len(groups) == 1 {
+		return true
+	}
+	if groups[0] > 3 || groups[len(groups)-1] != 3 {
+		return false
+	}
+	for _, n := range groups[1 : len(groups)-1] {
+		if n != 3 && n != 2 {
+			return false
+		}
+	}
+	return true
+}
+
+func cutPercent(s string) (string, bool) {
+	for _, sign := range []string{"%", "٪", "％"} {
+		if trimmed, ok := strings.CutSuffix(s, sign); ok {
+			return strings.TrimRightFunc(trimmed, unicode.IsSpace), true
+		}
+	}
+	return s, false
+}
+
+func trimCurrency(s string) string {
+	return strings.TrimFunc(s, func(r rune) bool {
+		return unicode.Is(unicode.Sc, r) || unicode.IsSpace(r)
+	})
+}
+
+func cutSign(s string) (string, bool) {
+	switch {
+	case strings.HasPrefix(s, "-"):
+		return s[1:], true
+	case strings.HasPrefix(s, "−"):
+		return s[len("−"):], true
+	case strings.HasPrefix(s, "+"):
+		return s[1:], false
+	}
+	return s, false
+}
+
+// digitValue returns the value of a decimal digit in any script, digits are encoded in runs of ten starting at zero
+func digitValue(r rune) (int, bool) {
+	if r >= '0' && r <= '9' {
+		return int(r - '0'), true
+	}
+	if !unicode.IsDigit(r) {
+		return 0, false
+	}
+	for _, rng := range unicode.Nd.R16 {
+		if r >= rune(rng.Lo) && r <= rune(rng.Hi) {
+			return int(r-rune(rng.Lo)) % 10, true
+		}
+	}
+	for _, rng := range unicode.Nd.R32 {
+		if r >= rune(rng.Lo) && r <= rune(rng.Hi) {
+			return int(r-rune(rng.Lo)) % 10, true
+		}
+	}
+	return 0, false
+}
@@ -17,11 +17,15 @@ package ursa
 // along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
+	"fmt"
 	"io"
+	"maps"
+	"math/big"
 	"mime/multipart"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"reflect"
 	"slices"
@@ -46,6 +50,15 @@ type ObjectParseResult interface {
 
 type File struct {
 	Header *multipart.FileHeader
+	ctx    context.Context
+}
+
+// Context returns the context of the request the file was uploaded with
+func (f File) Context() context.Context {
+	if f.ctx == nil {
+		return context.Background()
+	}
+	return f.ctx
 }
 
 type objectValidatorOpt func(o *objectValidator) error
@@ -57,7 +70,14 @@ type objectValidator struct {
 	validators  map[string]genericValidator[any]
 	refiners    []objectRefinerFunc
 	maxBodySize int64
+	queryMerge  QueryMergePolicy
+	format      string
+	jsonLimits  jsonLimits
+	fieldRefs   []fieldRef
 	err         error
+
+	numberFormat       *numberFormat
+	detectNumberFormat bool
 }
 
 type objectParseResult struct {
@@ -65,6 +85,15 @@ type objectParseResult struct {
 	fields []string // use this to preserve order
 }
 
+func invalidObjectResult(errs ...*parseError) *objectParseResult {
+	return &objectParseResult{
+		parseResult: parseResult[map[string]*parseResult[any]]{
+			valid:  false,
+			errors: errs,
+		},
+	}
+}
+
 func (r *objectParseResult) set(val any) {
 	if val, ok := val.(map[string]*parseResult[any]); ok {
 		r.value = val
@@ -92,16 +121,21 @@ func (o *objectParseResult) GetString(field string) string {
 	if val == nil {
 		return ""
 	}
+	if r, ok := val.(*big.Rat); ok {
+		return formatRat(r)
+	}
 	vo := reflect.ValueOf(val)
 	switch vo.Kind() {
 	case reflect.String:
 		return vo.String()
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return strconv.FormatInt(vo.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(vo.Uint(), 10)
 	case reflect.Float32, reflect.Float64:
 		return strconv.FormatFloat(vo.Float(), 'f', -1, 64)
 	}
-	return o.value[field].Get().(string)
+	return fmt.Sprint(val)
 }
 
 func (o *objectParseResult) GetInt(field string) int {
@@ -111,8 +145,12 @@ func (o *objectParseResult) GetInt(field string) int {
 	}
 	vo := reflect.ValueOf(val)
 	switch vo.Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return int(vo.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(vo.Uint())
+	case reflect.Float32, reflect.Float64:
+		return int(vo.Float())
 	case reflect.String:
 		i, err := strconv.ParseInt(vo.String(), 10, 64)
 		if err != nil {
@@ -128,15 +166,15 @@ func (o *objectParseResult) GetBool(field string) bool {
 	if val == nil {
 		return false
 	}
+	if b, ok := val.(OptionalBool); ok {
+		return b == BoolTrue
+	}
 	vo := reflect.ValueOf(val)
 	switch vo.Kind() {
 	case reflect.Bool:
 		return vo.Bool()
 	case reflect.String:
-		if len(vo.String()) == 0 {
-			return false
-		}
-		b, err := strconv.ParseBool(vo.String())
+		b, err := coerceToBool(vo.String())
 		if err != nil {
 			return false
 		}
@@ -211,15 +249,36 @@ func (r *objectParseResult) unmarshalToStruct(target interface{}) error {
 				if _, ok := r.value[sourceFieldName]; !ok {
 					continue
 				}
+				val := r.value[sourceFieldName].Get()
 				if field.Kind() == reflect.Struct {
-					if res, ok := r.value[sourceFieldName].Get().(*objectParseResult); ok {
-						if err := res.Unmarshal(field.Addr().Interface()); err != nil {
+					// nested objects are unmarshalled recursively, other structs e.g. time.Time are set directly
+					var nested *objectParseResult
+					switch val := val.(type) {
+					case *objectParseResult:
+						nested = val
+					case map[string]*parseResult[any]:
+						nested = &objectParseResult{parseResult: parseResult[map[string]*parseResult[any]]{valid: true, value: val}}
+					}
+					if nested != nil {
+						if err := nested.Unmarshal(field.Addr().Interface()); err != nil {
 							return err
 						}
+						break
 					}
-					continue
 				}
-				field.Set(reflect.ValueOf(r.GetField(sourceFieldName).Get()))
+				if val == nil {
+					break
+				}
+				vo := reflect.ValueOf(val)
+				switch {
+				case vo.Type().AssignableTo(field.Type()):
+					field.Set(vo)
+				case vo.Kind() == field.Kind() && vo.Type().ConvertibleTo(field.Type()):
+					// named types e.g. type Plan string
+					field.Set(vo.Convert(field.Type()))
+				default:
+					return fmt.Errorf("cannot unmarshal %s into field %s of type %s", vo.Type(), fieldName, field.Type())
+				}
 				break
 			}
 		}
@@ -241,6 +300,7 @@ func Object(opts ...any) *objectValidator {
 		validators:  make(map[string]genericValidator[interface{}]),
 		refiners:    make([]objectRefinerFunc, 0),
 		maxBodySize: 1024 * 1024 * 10,
+		format:      FormatJSON,
 	}
 	for _, opt := range opts {
 		switch opt := opt.(type) {
@@ -255,6 +315,17 @@ func Object(opts ...any) *objectValidator {
 }
 
 func (o *objectValidator) Parse(val any, opts ...parseOpt[any]) *objectParseResult {
+	switch val := val.(type) {
+	case []byte:
+		return o.ParseFormat(val, o.format)
+	case *http.Request:
+		return o.parseRequest(val)
+	}
+	return o.parse(val, o.numberFormat)
+}
+
+// parse validates the fields, string values of numeric fields are normalised using the number format if one is given
+func (o *objectValidator) parse(val any, format *numberFormat) *objectParseResult {
 	parseRes := &objectParseResult{
 		parseResult: parseResult[map[string]*parseResult[any]]{
 			valid:  true,
@@ -264,18 +335,12 @@ func (o *objectValidator) Parse(val any, opts ...parseOpt[any]) *objectParseResu
 		fields: o.fields,
 	}
 
-	if o.err != nil {
-		parseRes.errors = []*parseError{InvalidValidatorStateError}
+	if err := o.Error(); err != nil {
+		parseRes.valid = false
+		parseRes.errors = []*parseError{validatorStateError(err)}
 		return parseRes
 	}
 
-	switch val := val.(type) {
-	case []byte:
-		return o.parseJSON(val)
-	case *http.Request:
-		return o.parseRequest(val)
-	}
-
 	// run each validator in order
 	for _, name := range o.fields {
 		validator := o.validators[name]
@@ -291,8 +356,23 @@ func (o *objectValidator) Parse(val any, opts ...parseOpt[any]) *objectParseResu
 				},
 			}
 		} else {
-			res := validator.Parse(fieldVal)
-			fieldResult = &parseResult[interface{}]{valid: res.IsValid(), value: res.Get(), errors: res.Errors()}
+			var res genericParseResult[any]
+			var normErr *parseError
+			if nested, ok := validator.(*objectValidatorWrapper); ok && format != nil {
+				res = nested.parseWithFormat(fieldVal, format)
+			} else {
+				if format != nil && isNumericType(validator.Type()) {
+					fieldVal, normErr = format.normalizeValue(fieldVal, validator.Type())
+				}
+				if normErr == nil {
+					res = validator.Parse(fieldVal)
+				}
+			}
+			if normErr != nil {
+				fieldResult = &parseResult[interface{}]{errors: []*parseError{normErr}}
+			} else {
+				fieldResult = &parseResult[interface{}]{valid: res.IsValid(), value: res.Get(), errors: res.Errors()}
+			}
 		}
 		parseRes.value[name] = fieldResult
 		parseRes.errors = append(parseRes.errors, fieldResult.errors...)
@@ -312,8 +392,12 @@ func (o *objectValidator) Parse(val any, opts ...parseOpt[any]) *objectParseResu
 	return parseRes
 }
 
+// Error reports a problem with the schema e.g. an option which failed or a comparison with an undeclared field
 func (o *objectValidator) Error() error {
-	return nil
+	if o.err != nil {
+		return o.err
+	}
+	return o.checkFieldRefs()
 }
 
 func (o *objectValidator) Type() reflect.Type {
@@ -348,21 +432,80 @@ func (o *objectValidator) extract(val any, name string) (any, error) {
 	return v.Interface(), nil
 }
 
-func (o *objectValidator) parseJSON(val []byte, opts ...parseOpt[any]) *objectParseResult {
-	unpacked := make(map[string]interface{})
-	err := json.Unmarshal(val, &unpacked)
+// ParseFormat decodes the data using the decoder registered for the media type and validates the result
+func (o *objectValidator) ParseFormat(data []byte, mediaType string, opts ...parseOpt[any]) *objectParseResult {
+	if err := o.Error(); err != nil {
+		return invalidObjectResult(validatorStateError(err))
+	}
+	if int64(len(data)) > o.maxBodySize {
+		return invalidObjectResult(&parseError{message: "request body too large"})
+	}
+	unpacked, err := o.decodeBytes(data, mediaType)
 	if err != nil {
-		return &objectParseResult{
-			parseResult: parseResult[map[string]*parseResult[any]]{
-				valid:  false,
-				errors: []*parseError{{message: "unmarshalling JSON value", inner: []error{err}}},
-			},
-		}
+		return invalidObjectResult(err)
 	}
 	return o.Parse(unpacked, opts...)
 }
 
 func (o *objectValidator) parseRequest(req *http.Request, opts ...parseOpt[any]) *objectParseResult {
+	if err := o.Error(); err != nil {
+		return invalidObjectResult(validatorStateError(err))
+	}
+	data, err := o.decodeRequest(req)
+	if err != nil {
+		return invalidObjectResult(err)
+	}
+	return o.parse(data, o.requestNumberFormat(req))
+}
+
+// decodeRequest extracts the raw values from the request body and merges them with the query string
+func (o *objectValidator) decodeRequest(req *http.Request) (map[string]interface{}, *parseError) {
+	query := o.readForm(req.URL.Query())
+	if !hasBody(req) {
+		return query, nil
+	}
+
+	data, err := o.decodeBody(req)
+	if err == UnsupportedContentTypeError && req.Method == "GET" {
+		return query, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return o.mergeQuery(data, query)
+}
+
+func (o *objectValidator) mergeQuery(body, query map[string]interface{}) (map[string]interface{}, *parseError) {
+	if o.queryMerge == QueryIgnored {
+		return body, nil
+	}
+	conflicts := make([]string, 0)
+	for _, k := range slices.Sorted(maps.Keys(query)) {
+		if _, ok := body[k]; !ok {
+			body[k] = query[k]
+			continue
+		}
+		switch o.queryMerge {
+		case QueryWins:
+			body[k] = query[k]
+		case QueryConflictError:
+			conflicts = append(conflicts, k)
+		}
+	}
+	if len(conflicts) > 0 {
+		// the path is the first conflicting key in sorted order, all of them are in the params
+		return nil, &parseError{message: "query parameter conflicts with request body", path: conflicts[0], params: map[string]any{"keys": conflicts}}
+	}
+	return body, nil
+}
+
+// hasBody reports whether the request has content, a Content-Type header on its own isn't enough
+func hasBody(req *http.Request) bool {
+	return req.Body != nil && req.Body != http.NoBody && req.ContentLength != 0
+}
+
+func (o *objectValidator) decodeBody(req *http.Request) (map[string]interface{}, *parseError) {
 	contentType := strings.TrimSpace(strings.Split(req.Header.Get("Content-Type"), ";")[0])
 
 	body := req.Body
@@ -372,86 +515,92 @@ func (o *objectValidator) parseRequest(req *http.Request, opts ...parseOpt[any])
 
 	numBytes := req.ContentLength
 	if numBytes > o.maxBodySize {
-		return &objectParseResult{
-			parseResult: parseResult[map[string]*parseResult[any]]{
-				errors: []*parseError{{message: "request body too large"}},
-			},
-		}
+		return nil, &parseError{message: "request body too large"}
 	}
 
 	switch contentType {
-	case "application/json":
-		buf, err := o.readBody(body, int(numBytes))
-		if err != nil {
-			return &objectParseResult{
-				parseResult: parseResult[map[string]*parseResult[any]]{
-					errors: []*parseError{err},
-				},
-			}
-		}
-		return o.parseJSON(buf, opts...)
-
 	case "application/x-www-form-urlencoded":
 		err := req.ParseForm()
 		if err != nil {
-			return &objectParseResult{
-				parseResult: parseResult[map[string]*parseResult[any]]{
-					errors: []*parseError{{message: "parsing form", inner: []error{err}}},
-				},
-			}
+			return nil, &parseError{message: "parsing form", inner: []error{err}}
 		}
-		return o.Parse(o.readForm(req.Form), opts...)
+		return o.readForm(req.PostForm), nil
 
 	case "multipart/form-data":
 		err := req.ParseMultipartForm(o.maxBodySize)
 		if err != nil {
-			return &objectParseResult{
-				parseResult: parseResult[map[string]*parseResult[any]]{
-					errors: []*parseError{{message: "parsing multipart form", inner: []error{err}}},
-				},
-			}
+			return nil, &parseError{message: "parsing multipart form", inner: []error{err}}
 		}
 
-		formData := o.readForm(req.Form)
+		formData := o.readForm(req.PostForm)
 		for name, fileHeaders := range req.MultipartForm.File {
 			if _, ok := formData[name]; !ok {
 				formData[name] = make([]File, 0, len(fileHeaders))
 			}
 			for _, fileHeader := range fileHeaders {
-				formData[name] = append(formData[name].([]File), File{Header: fileHeader})
+				formData[name] = append(formData[name].([]File), File{Header: fileHeader, ctx: req.Context()})
 			}
 		}
 
-		return o.Parse(formData, opts...)
+		return formData, nil
 
 	default:
-		if req.Method == "GET" {
-			err := req.ParseForm()
-			if err != nil {
-				return &objectParseResult{
-					parseResult: parseResult[map[string]*parseResult[any]]{
-						errors: []*parseError{{message: "parsing form", inner: []error{err}}},
-					},
-				}
-			}
-			return o.Parse(o.readForm(req.Form), opts...)
+		if !canDecode(contentType) {
+			return nil, UnsupportedContentTypeError
 		}
-		return &objectParseResult{
-			parseResult: parseResult[map[string]*parseResult[any]]{
-				errors: []*parseError{{message: "unsupported content type"}},
-			},
+		buf, err := o.readBody(body)
+		if err != nil {
+			return nil, err
 		}
+		return o.decodeBytes(buf, contentType)
+	}
+}
+
+func canDecode(mediaType string) bool {
+	mediaType = resolveMediaType(mediaType)
+	if mediaType == FormatJSON {
+		return true
+	}
+	_, ok := lookupDecoder(mediaType)
+	return ok
+}
+
+func (o *objectValidator) decodeBytes(data []byte, mediaType string) (map[string]interface{}, *parseError) {
+	mediaType = resolveMediaType(mediaType)
+	if mediaType == FormatJSON {
+		return o.decodeJSON(data)
+	}
+
+	dec, ok := lookupDecoder(mediaType)
+	if !ok {
+		return nil, UnsupportedContentTypeError
+	}
+
+	maxDepth := o.jsonLimits.depthLimit()
+	unpacked, err := dec(data, maxDepth)
+	if errors.Is(err, ErrDecodeTooDeep) {
+		return nil, DocumentTooDeepError
 	}
+	if err != nil {
+		return nil, &parseError{message: "decoding " + mediaType + " value", inner: []error{err}}
+	}
+	if exceedsDepth(unpacked, 1, maxDepth) {
+		return nil, DocumentTooDeepError
+	}
+	return unpacked, nil
 }
 
-func (o *objectValidator) readBody(body io.ReadCloser, size int) ([]byte, *parseError) {
-	buf := make([]byte, size)
-	numRead, err := io.ReadFull(body, buf)
+func (o *objectValidator) readBody(body io.ReadCloser) ([]byte, *parseError) {
+	if body == nil {
+		return []byte{}, nil
+	}
+	// the content length may be missing or wrong so never trust it
+	buf, err := io.ReadAll(io.LimitReader(body, o.maxBodySize+1))
 	if err != nil {
 		return nil, &parseError{message: "reading request body", inner: []error{err}}
 	}
-	if numRead != size {
-		return nil, &parseError{message: "request body size mismatch"}
+	if int64(len(buf)) > o.maxBodySize {
+		return nil, &parseError{message: "request body too large"}
 	}
 	return buf, nil
 }
@@ -541,29 +690,182 @@ func (o *objectValidator) Float64(name string, opts ...any) *objectValidator {
 	return o
 }
 
+func (o *objectValidator) Decimal(name string, opts ...any) *objectValidator {
+	fv := &validatorWrapper[*big.Rat]{validator: decimalValidatorFactory(opts...)}
+	o.fields = append(o.fields, name)
+	o.validators[name] = fv
+	return o
+}
+
+func (o *objectValidator) BigInt(name string, opts ...any) *objectValidator {
+	fv := &validatorWrapper[*big.Int]{validator: bigIntValidatorFactory(opts...)}
+	o.fields = append(o.fields, name)
+	o.validators[name] = fv
+	return o
+}
+
+func (o *objectValidator) Money(name string, opts ...any) *objectValidator {
+	fv := &validatorWrapper[MonetaryAmount]{validator: moneyValidatorFactory(opts...)}
+	o.fields = append(o.fields, name)
+	o.validators[name] = fv
+	return o
+}
+
+// fieldRef records a comparison between two fields so the other field can be checked once the schema is used
+type fieldRef struct {
+	field, other string
+}
+
+// addFieldRefiners removes the options which compare the field with another field and adds them as refiners
+func (o *objectValidator) addFieldRefiners(name string, opts []any) []any {
+	remaining := make([]any, 0, len(opts))
+	for _, opt := range opts {
+		if opt, ok := opt.(fieldRefinerOpt); ok {
+			o.refiners = append(o.refiners, opt.refine(name))
+			o.fieldRefs = append(o.fieldRefs, fieldRef{field: name, other: opt.other})
+			continue
+		}
+		remaining = append(remaining, opt)
+	}
+	return remaining
+}
+
+// checkFieldRefs reports a comparison with a field which isn't declared or which has a different type, the other
+// field may be declared after the field which refers to it so this is checked when the schema is used
+func (o *objectValidator) checkFieldRefs() error {
+	for _, ref := range o.fieldRefs {
+		other, ok := o.validators[ref.other]
+		if !ok {
+			return fmt.Errorf("field %q is compared with %q which is not declared", ref.field, ref.other)
+		}
+		if t := o.validators[ref.field].Type(); other.Type() != t {
+			return fmt.Errorf("field %q is a %s and can't be compared with %q which is a %s", ref.field, t, ref.other, other.Type())
+		}
+	}
+	return nil
+}
+
 func (o *objectValidator) Time(name string, opts ...any) *objectValidator {
-	fv := &validatorWrapper[time.Time]{validator: validatorFactory[time.Time](opts...)}
+	fv := &validatorWrapper[time.Time]{validator: timeValidatorFactory(o.addFieldRefiners(name, opts)...)}
+	o.fields = append(o.fields, name)
+	o.validators[name] = fv
+	return o
+}
+
+func (o *objectValidator) Date(name string, opts ...any) *objectValidator {
+	fv := &validatorWrapper[CalendarDate]{validator: dateValidatorFactory(o.addFieldRefiners(name, opts)...)}
+	o.fields = append(o.fields, name)
+	o.validators[name] = fv
+	return o
+}
+
+func (o *objectValidator) TimeOfDay(name string, opts ...any) *objectValidator {
+	fv := &validatorWrapper[ClockTime]{validator: timeOfDayValidatorFactory(opts...)}
+	o.fields = append(o.fields, name)
+	o.validators[name] = fv
+	return o
+}
+
+func (o *objectValidator) Duration(name string, opts ...any) *objectValidator {
+	fv := &validatorWrapper[time.Duration]{validator: durationValidatorFactory(opts...)}
+	o.fields = append(o.fields, name)
+	o.validators[name] = fv
+	return o
+}
+
+func (o *objectValidator) Interval(name string, opts ...any) *objectValidator {
+	fv := &validatorWrapper[TimeInterval]{validator: intervalValidatorFactory(opts...)}
+	o.fields = append(o.fields, name)
+	o.validators[name] = fv
+	return o
+}
+
+func (o *objectValidator) IPAddr(name string, opts ...any) *objectValidator {
+	fv := &validatorWrapper[netip.Addr]{validator: ipAddrValidatorFactory(opts...)}
+	o.fields = append(o.fields, name)
+	o.validators[name] = fv
+	return o
+}
+
+func (o *objectValidator) IPPrefix(name string, opts ...any) *objectValidator {
+	fv := &validatorWrapper[netip.Prefix]{validator: ipPrefixValidatorFactory(opts...)}
+	o.fields = append(o.fields, name)
+	o.validators[name] = fv
+	return o
+}
+
+func (o *objectValidator) ParsedURL(name string, opts ...any) *objectValidator {
+	fv := &validatorWrapper[*url.URL]{validator: urlValidatorFactory(opts...)}
+	o.fields = append(o.fields, name)
+	o.validators[name] = fv
+	return o
+}
+
+func (o *objectValidator) HostPort(name string, opts ...any) *objectValidator {
+	fv := &validatorWrapper[HostAndPort]{validator: hostPortValidatorFactory(opts...)}
+	o.fields = append(o.fields, name)
+	o.validators[name] = fv
+	return o
+}
+
+func (o *objectValidator) LatLng(name string, opts ...any) *objectValidator {
+	fv := &validatorWrapper[GeoPoint]{validator: latLngValidatorFactory(opts...)}
+	o.fields = append(o.fields, name)
+	o.validators[name] = fv
+	return o
+}
+
+func (o *objectValidator) GeoJSON(name string, opts ...any) *objectValidator {
+	fv := &validatorWrapper[Geometry]{validator: geoJSONValidatorFactory(opts...)}
 	o.fields = append(o.fields, name)
 	o.validators[name] = fv
 	return o
 }
 
 func (o *objectValidator) UUID(name string, opts ...any) *objectValidator {
-	fv := &validatorWrapper[uuid.UUID]{validator: validatorFactory[uuid.UUID](opts...)}
+	fv := &validatorWrapper[uuid.UUID]{validator: uuidValidatorFactory(opts...)}
 	o.fields = append(o.fields, name)
 	o.validators[name] = fv
 	return o
 }
 
 func (o *objectValidator) Bool(name string, opts ...any) *objectValidator {
-	fv := &validatorWrapper[bool]{validator: validatorFactory[bool](opts...)}
+	fv := &validatorWrapper[bool]{validator: boolValidatorFactory(opts...)}
+	o.fields = append(o.fields, name)
+	o.validators[name] = fv
+	return o
+}
+
+func (o *objectValidator) TriStateBool(name string, opts ...any) *objectValidator {
+	fv := &validatorWrapper[OptionalBool]{validator: triStateBoolValidatorFactory(opts...)}
 	o.fields = append(o.fields, name)
 	o.validators[name] = fv
 	return o
 }
 
+// Object adds a nested object, the schema can be passed as an option e.g. Object("address", Object().String("city")),
+// any object options e.g. WithNumberFormat are applied to the schema
 func (o *objectValidator) Object(name string, opts ...any) *objectValidator {
-	fv := Object(opts...)
+	var fv *objectValidator
+	remaining := make([]any, 0, len(opts))
+	for _, opt := range opts {
+		if schema, ok := opt.(*objectValidator); ok {
+			fv = schema
+			continue
+		}
+		remaining = append(remaining, opt)
+	}
+	if fv == nil {
+		fv = Object(remaining...)
+	} else {
+		for _, opt := range remaining {
+			if opt, ok := opt.(objectValidatorOpt); ok {
+				if err := opt(fv); err != nil {
+					fv.err = err
+				}
+			}
+		}
+	}
 	wrapper := &objectValidatorWrapper{validator: fv}
 	o.fields = append(o.fields, name)
 	o.validators[name] = wrapper
@@ -685,6 +987,19 @@ func (v *objectValidatorWrapper) Parse(val any, opts ...parseOpt[interface{}]) g
 	return wrappedRes
 }
 
+// parseWithFormat passes the number format of the parent object down unless the nested object has its own
+func (v *objectValidatorWrapper) parseWithFormat(val any, format *numberFormat) genericParseResult[interface{}] {
+	switch val.(type) {
+	case []byte, *http.Request:
+		return v.Parse(val)
+	}
+	if v.validator.numberFormat != nil {
+		format = v.validator.numberFormat
+	}
+	res := v.validator.parse(val, format)
+	return &parseResult[interface{}]{valid: res.IsAllValid(), value: res.Get(), errors: res.Errors()}
+}
+
 func (v *objectValidatorWrapper) Error() error {
 	return v.validator.Error()
 }
@@ -693,6 +1008,31 @@ func (v *objectValidatorWrapper) Type() reflect.Type {
 	return v.validator.Type()
 }
 
+// QueryMergePolicy controls how query string values are combined with values from the request body
+type QueryMergePolicy int
+
+const (
+	QueryBodyWins      QueryMergePolicy = iota // the body value is used when a key appears in both
+	QueryWins                                  // the query string value is used when a key appears in both
+	QueryConflictError                         // a key which appears in both is an error
+	QueryIgnored                               // the query string is only used when the request has no body
+)
+
+func WithQueryMerge(policy QueryMergePolicy) objectValidatorOpt {
+	return func(o *objectValidator) error {
+		o.queryMerge = policy
+		return nil
+	}
+}
+
+// WithFormat sets the media type used to decode []byte values passed to Parse, the default is JSON
+func WithFormat(mediaType string) objectValidatorOpt {
+	return func(o *objectValidator) error {
+		o.format = mediaType
+		return nil
+	}
+}
+
 func WithMaxBodySize(size int64) objectValidatorOpt {
 	return func(o *objectValidator) error {
 		o.maxBodySize = size
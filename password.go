@@ -0,0 +1,543 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy configures the Password validator, the zero value only applies the length limits
+type PasswordPolicy struct {
+	// MinLength and MaxLength count runes, a MaxLength of zero means no limit
+	MinLength int
+	MaxLength int
+
+	// character class rules, NIST 800-63B recommends against these so they are off by default
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+
+	// MinScore is the minimum strength from 0 (too guessable) to 4 (very unguessable), see EstimatePasswordStrength
+	MinScore int
+	// RejectCommon rejects passwords which are in the list of commonly used passwords
+	RejectCommon bool
+	// UserInputs are words which must not appear in the password e.g. the name of the site
+	UserInputs []string
+
+	// BreachChecker is consulted after all other checks pass, if the check fails the password is
+	// rejected unless AllowOnBreachCheckError is set
+	BreachChecker           BreachChecker
+	AllowOnBreachCheckError bool
+	// BreachCheckTimeout is the time allowed for the breach check, DefaultBreachCheckTimeout is used if it is zero
+	BreachCheckTimeout time.Duration
+}
+
+// NISTPasswordPolicy follows NIST 800-63B, at least 8 characters, up to 64 allowed, no composition
+// rules and a check against common passwords
+func NISTPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:    8,
+		MaxLength:    64,
+		MinScore:     2,
+		RejectCommon: true,
+	}
+}
+
+// MaxPasswordLength is the longest password accepted when the policy doesn't set a MaxLength, it bounds the work
+// done by the strength estimate and the breach check
+const MaxPasswordLength = 1024
+
+// DefaultBreachCheckTimeout is the time allowed for a breach check when the policy doesn't set one
+var DefaultBreachCheckTimeout = 5 * time.Second
+
+// BreachChecker reports whether a password has appeared in a data breach
+type BreachChecker interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+func passwordError(code, message string, params map[string]any) *parseError {
+	return &parseError{message: message, code: code, params: params}
+}
+
+// Password checks the password against the policy, the message replaces the message of whichever check fails
+func Password(policy PasswordPolicy, message ...string) stringValidatorOpt {
+	fail := func(code, defaultMessage string, params map[string]any, inner ...error) *parseError {
+		err := passwordError(code, defaultMessage, params)
+		if len(message) > 0 {
+			err.message = message[0]
+		}
+		if len(inner) > 0 {
+			err.inner = inner
+		}
+		return err
+	}
+
+	return func(val *string) *parseError {
+		if val == nil {
+			return nil
+		}
+		password := *val
+
+		length := utf8.RuneCountInString(password)
+		if length < policy.MinLength {
+			return fail("password_too_short", "password too short", map[string]any{"min": policy.MinLength})
+		}
+		maxLength := policy.MaxLength
+		if maxLength <= 0 || maxLength > MaxPasswordLength {
+			maxLength = MaxPasswordLength
+		}
+		if length > maxLength {
+			return fail("password_too_long", "password too long", map[string]any{"max": maxLength})
+		}
+
+		classes := passwordClasses(password)
+		switch {
+		case policy.RequireUpper && !classes.upper:
+			return fail("password_missing_upper", "password must contain an upper case letter", nil)
+		case policy.RequireLower && !classes.lower:
+			return fail("password_missing_lower", "password must contain a lower case letter", nil)
+		case policy.RequireDigit && !classes.digit:
+			return fail("password_missing_digit", "password must contain a digit", nil)
+		case policy.RequireSymbol && !classes.symbol:
+			return fail("password_missing_symbol", "password must contain a symbol", nil)
+		}
+
+		if policy.RejectCommon && isCommonPassword(password) {
+			return fail("password_common", "password is too common", nil)
+		}
+		for _, input := range policy.UserInputs {
+			if containsUserInput(password, input) {
+				return fail("password_contains_user_data", "password contains personal information", nil)
+			}
+		}
+
+		if policy.MinScore > 0 {
+			strength := EstimatePasswordStrength(password, policy.UserInputs...)
+			if strength.Score < policy.MinScore {
+				return fail("password_too_weak", "password too weak", map[string]any{"score": strength.Score, "minScore": policy.MinScore})
+			}
+		}
+
+		if policy.BreachChecker != nil {
+			timeout := policy.BreachCheckTimeout
+			if timeout <= 0 {
+				timeout = DefaultBreachCheckTimeout
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			breached, err := policy.BreachChecker.IsBreached(ctx, password)
+			if err != nil && !policy.AllowOnBreachCheckError {
+				return fail("password_check_failed", "password could not be checked", nil, err)
+			}
+			if breached {
+				return fail("password_breached", "password has appeared in a data breach", nil)
+			}
+		}
+
+		return nil
+	}
+}
+
+// PasswordExcludesFields is a refiner which rejects a password containing the value of any of the fields e.g. the email address or name
+func PasswordExcludesFields(passwordField string, fields ...string) objectRefinerFunc {
+	return func(res ObjectParseResult) {
+		r, ok := res.(*objectParseResult)
+		if !ok {
+			return
+		}
+		passwordRes, ok := r.value[passwordField]
+		if !ok || !passwordRes.valid {
+			return
+		}
+		password, _ := passwordRes.value.(string)
+
+		for _, field := range fields {
+			fieldRes, ok := r.value[field]
+			if !ok {
+				continue
+			}
+			input, _ := fieldRes.value.(string)
+			if !containsUserInput(password, input) {
+				continue
+			}
+			err := passwordError("password_contains_user_data", "password contains personal information", map[string]any{"field": field})
+			passwordRes.valid = false
+			passwordRes.errors = append(passwordRes.errors, err)
+			r.valid = false
+			r.errors = append(r.errors, err)
+			return
+		}
+	}
+}
+
+// minUserInputWordLength stops short words e.g. initials matching most passwords
+const minUserInputWordLength = 4
+
+// containsUserInput checks the password for the input and for each word in it e.g. the parts of a name or email address
+func containsUserInput(password, input string) bool {
+	password = strings.ToLower(password)
+	for _, word := range inputWords(input) {
+		if strings.Contains(password, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// inputWords returns the lower case input and each of the words in it which is long enough to be personal, only the
+// local part of an email address is split as the domain e.g. "example.com" is shared by many users
+func inputWords(input string) []string {
+	input = strings.ToLower(strings.TrimSpace(input))
+	local := input
+	if ix := strings.LastIndex(input, "@"); ix >= 0 {
+		local = input[:ix]
+	}
+	words := make([]string, 0)
+	for _, word := range strings.FieldsFunc(local, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if utf8.RuneCountInString(word) >= minUserInputWordLength {
+			words = append(words, word)
+		}
+	}
+	for _, word := range []string{local, input} {
+		if utf8.RuneCountInString(word) >= minUserInputWordLength && !slices.Contains(words, word) {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+type characterClasses struct {
+	lower, upper, digit, symbol, other bool
+}
+
+func passwordClasses(password string) characterClasses {
+	var c characterClasses
+	for _, r := range password {
+		switch {
+		case r >= 'a' && r <= 'z':
+			c.lower = true
+		case r >= 'A' && r <= 'Z':
+			c.upper = true
+		case r >= '0' && r <= '9':
+			c.digit = true
+		case r < utf8.RuneSelf:
+			c.symbol = true
+		case unicode.IsLower(r):
+			c.lower = true
+		case unicode.IsUpper(r):
+			c.upper = true
+		case unicode.IsDigit(r):
+			c.digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			c.symbol = true
+		default:
+			c.other = true
+		}
+	}
+	return c
+}
+
+// PasswordStrength is an estimate of how hard a password is to guess
+type PasswordStrength struct {
+	// Entropy is log2 of the estimated number of guesses
+	Entropy float64
+	// Score is 0 (fewer than 10^3 guesses) to 4 (at least 10^10 guesses), the same scale as zxcvbn
+	Score int
+}
+
+// maxScoredPasswordLength bounds the cost of matching, as in zxcvbn only the start of a long password is matched
+const maxScoredPasswordLength = 100
+
+// EstimatePasswordStrength splits the password into the cheapest combination of common passwords, user inputs,
+// repeats, sequences, keyboard runs and years, any remaining characters are counted as brute force guesses.
+// Only the first 100 characters are matched, the rest are counted as brute force guesses.
+func EstimatePasswordStrength(password string, userInputs ...string) PasswordStrength {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return PasswordStrength{}
+	}
+	bruteBits := math.Log2(float64(passwordClasses(password).cardinality()))
+	unscored := 0
+	if len(runes) > maxScoredPasswordLength {
+		unscored = len(runes) - maxScoredPasswordLength
+		runes = runes[:maxScoredPasswordLength]
+		password = string(runes)
+	}
+	lower := []rune(strings.Map(unicode.ToLower, password))
+	plain := []rune(unleet(password))
+
+	// matches are indexed by their end so each position only looks at the matches which finish there
+	byEnd := make([][]passwordMatch, len(runes)+1)
+	for _, m := range passwordMatches(runes, lower, plain, userInputs) {
+		byEnd[m.end] = append(byEnd[m.end], m)
+	}
+
+	// bits[i] is the lowest cost of the first i characters
+	bits := make([]float64, len(runes)+1)
+	for i := 1; i <= len(runes); i++ {
+		bits[i] = bits[i-1] + bruteBits
+		for _, m := range byEnd[i] {
+			if bits[m.start]+m.bits < bits[i] {
+				bits[i] = bits[m.start] + m.bits
+			}
+		}
+	}
+
+	entropy := bits[len(runes)] + float64(unscored)*bruteBits
+	score := 4
+	for i, threshold := range []float64{3, 6, 8, 10} {
+		if entropy < threshold*math.Log2(10) {
+			score = i
+			break
+		}
+	}
+	return PasswordStrength{Entropy: entropy, Score: score}
+}
+
+func (c characterClasses) cardinality() int {
+	n := 0
+	if c.lower {
+		n += 26
+	}
+	if c.upper {
+		n += 26
+	}
+	if c.digit {
+		n += 10
+	}
+	if c.symbol {
+		n += 33
+	}
+	if c.other {
+		n += 100
+	}
+	return n
+}
+
+type passwordMatch struct {
+	start, end int
+	bits       float64
+}
+
+var keyboardRows = []string{"1234567890", "qwertyuiop", "asdfghjkl", "zxcvbnm", "qwertzuiop", "azertyuiop"}
+
+// passwordMatches finds the guessable parts of the password, lower is the lower case password and plain also has
+// leet substitutions undone, both have a rune for each rune in the password
+func passwordMatches(runes, lower, plain []rune, userInputs []string) []passwordMatch {
+	matches := make([]passwordMatch, 0)
+
+	// dictionary words, an extra bit is added when the case or leet substitutions differ from the plain word
+	addWord := func(word string, rank int) {
+		if utf8.RuneCountInString(word) < 3 {
+			return
+		}
+		for _, candidate := range []string{string(lower), string(plain)} {
+			for offset := 0; ; {
+				ix := strings.Index(candidate[offset:], word)
+				if ix < 0 {
+					break
+				}
+				start := utf8.RuneCountInString(candidate[:offset+ix])
+				end := start + utf8.RuneCountInString(word)
+				cost := math.Log2(float64(rank + 1))
+				if string(runes[start:end]) != word {
+					cost++
+				}
+				matches = append(matches, passwordMatch{start: start, end: end, bits: cost})
+				offset += ix + 1
+			}
+		}
+	}
+	for rank, word := range commonPasswords {
+		addWord(word, rank+1)
+	}
+	for _, input := range userInputs {
+		for _, word := range inputWords(input) {
+			addWord(word, 1)
+		}
+	}
+
+	for start := 0; start < len(lower); start++ {
+		// repeats e.g. aaaa
+		end := start + 1
+		for end < len(lower) && lower[end] == lower[start] {
+			end++
+		}
+		if end-start >= 3 {
+			matches = append(matches, passwordMatch{start: start, end: end, bits: math.Log2(95) + math.Log2(float64(end-start))})
+		}
+
+		// sequences e.g. abcd or 9876
+		for _, step := range []rune{1, -1} {
+			end := start + 1
+			for end < len(lower) && lower[end]-lower[end-1] == step {
+				end++
+			}
+			if end-start >= 3 {
+				matches = append(matches, passwordMatch{start: start, end: end, bits: math.Log2(26) + math.Log2(float64(end-start)) + 1})
+			}
+		}
+
+		// years from 1900 to 2099
+		if start+4 <= len(lower) {
+			year := string(lower[start : start+4])
+			if (strings.HasPrefix(year, "19") || strings.HasPrefix(year, "20")) && strings.Trim(year, "0123456789") == "" {
+				matches = append(matches, passwordMatch{start: start, end: start + 4, bits: math.Log2(200)})
+			}
+		}
+	}
+
+	// keyboard runs e.g. qwerty or lkjh
+	for _, row := range keyboardRows {
+		reversed := []rune(row)
+		for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+			reversed[i], reversed[j] = reversed[j], reversed[i]
+		}
+		for _, r := range []string{row, string(reversed)} {
+			for length := len(r); length >= 4; length-- {
+				for i := 0; i+length <= len(r); i++ {
+					addKeyboardRun(&matches, string(lower), r[i:i+length])
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+func addKeyboardRun(matches *[]passwordMatch, lower, run string) {
+	for offset := 0; ; {
+		ix := strings.Index(lower[offset:], run)
+		if ix < 0 {
+			return
+		}
+		start := utf8.RuneCountInString(lower[:offset+ix])
+		*matches = append(*matches, passwordMatch{start: start, end: start + len(run), bits: math.Log2(float64(len(keyboardRows)*10)) + math.Log2(float64(len(run)))})
+		offset += ix + 1
+	}
+}
+
+var leetSubstitutions = map[rune]rune{'4': 'a', '@': 'a', '8': 'b', '3': 'e', '1': 'i', '!': 'i', '0': 'o', '5': 's', '$': 's', '7': 't', '+': 't'}
+
+// unleet lower cases the string and undoes common substitutions, each rune maps to a single rune so
+// positions in the result match the original
+func unleet(s string) string {
+	return strings.Map(func(r rune) rune {
+		if sub, ok := leetSubstitutions[r]; ok {
+			return sub
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+func isCommonPassword(password string) bool {
+	lower := strings.ToLower(password)
+	plain := unleet(password)
+	for _, common := range commonPasswords {
+		if lower == common || plain == common {
+			return true
+		}
+	}
+	return false
+}
+
+// commonPasswords is ordered by how frequently the password is used
+var commonPasswords = []string{
+	"123456", "password", "123456789", "12345678", "12345", "qwerty", "1234567", "111111", "1234567890", "123123",
+	"abc123", "1234", "password1", "iloveyou", "1q2w3e4r", "000000", "qwerty123", "zaq12wsx", "dragon", "sunshine",
+	"princess", "letmein", "654321", "monkey", "987654", "1qaz2wsx", "123321", "qwertyuiop", "superman", "asdfghjkl",
+	"football", "baseball", "welcome", "admin", "master", "shadow", "ashley", "michael", "jennifer", "hunter",
+	"trustno1", "passw0rd", "starwars", "whatever", "freedom", "charlie", "donald", "batman", "zxcvbnm", "access",
+	"login", "hello", "qazwsx", "mustang", "flower", "hottie", "loveme", "solo", "121212", "666666",
+	"696969", "987654321", "lovely", "bailey", "jordan", "harley", "ranger", "buster", "thomas", "tigger",
+	"robert", "soccer", "hockey", "killer", "george", "andrew", "michelle", "jessica", "pepper", "daniel",
+	"cheese", "computer", "internet", "summer", "winter", "secret", "changeme", "default", "pokemon", "matrix",
+	"liverpool", "chelsea", "arsenal", "purple", "orange", "yellow", "silver", "golden", "cookie", "chocolate",
+	"qwertyui", "asdfgh", "iloveu", "monkey123", "password123", "admin123", "welcome1", "letmein1", "p@ssword", "aa123456",
+}
+
+// HIBPChecker uses the k-anonymity range API of Have I Been Pwned, only the first five characters of the
+// SHA-1 hash of the password are sent
+type HIBPChecker struct {
+	BaseURL string
+	Client  *http.Client
+	// Timeout limits each request as well as the context passed to IsBreached, zero means no extra limit
+	Timeout time.Duration
+}
+
+func NewHIBPChecker() *HIBPChecker {
+	return &HIBPChecker{
+		BaseURL: "https://api.pwnedpasswords.com",
+		Client:  http.DefaultClient,
+	}
+}
+
+func (c *HIBPChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", strings.TrimSuffix(c.BaseURL, "/")+"/range/"+prefix, nil)
+	if err != nil {
+		return false, err
+	}
+	// padding hides the number of results from anyone observing the response size
+	req.Header.Set("Add-Padding", "true")
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("breach check returned status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		// padding entries have a count of zero
+		if ok && strings.EqualFold(candidate, suffix) && count != "0" {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
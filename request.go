@@ -0,0 +1,185 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"errors"
+	"net/http"
+)
+
+const (
+	SourcePath   = "path"
+	SourceQuery  = "query"
+	SourceHeader = "header"
+	SourceCookie = "cookie"
+	SourceBody   = "body"
+)
+
+type requestSection struct {
+	source string
+	schema *objectValidator
+}
+
+// requestValidator validates the different parts of an HTTP request, each with its own object schema
+type requestValidator struct {
+	sections []requestSection
+	err      error
+}
+
+func Request() *requestValidator {
+	return &requestValidator{
+		sections: make([]requestSection, 0),
+	}
+}
+
+// Path validates the route parameters e.g. {id} in "GET /items/{id}"
+func (r *requestValidator) Path(schema *objectValidator) *requestValidator {
+	return r.section(SourcePath, schema)
+}
+
+func (r *requestValidator) Query(schema *objectValidator) *requestValidator {
+	return r.section(SourceQuery, schema)
+}
+
+func (r *requestValidator) Header(schema *objectValidator) *requestValidator {
+	return r.section(SourceHeader, schema)
+}
+
+func (r *requestValidator) Cookie(schema *objectValidator) *requestValidator {
+	return r.section(SourceCookie, schema)
+}
+
+func (r *requestValidator) Body(schema *objectValidator) *requestValidator {
+	return r.section(SourceBody, schema)
+}
+
+func (r *requestValidator) section(source string, schema *objectValidator) *requestValidator {
+	// all of the fields end up in a single result so names must be unique across sections
+	for _, s := range r.sections {
+		for _, name := range schema.fields {
+			if _, ok := s.schema.validators[name]; ok {
+				r.err = errors.New("duplicate request field: " + name)
+			}
+		}
+	}
+	r.sections = append(r.sections, requestSection{source: source, schema: schema})
+	return r
+}
+
+func (r *requestValidator) Error() error {
+	return r.err
+}
+
+// Parse validates each section of the request and merges the fields into a single result, error paths are prefixed with the source
+func (r *requestValidator) Parse(req *http.Request) *objectParseResult {
+	parseRes := &objectParseResult{
+		parseResult: parseResult[map[string]*parseResult[any]]{
+			valid:  true,
+			value:  make(map[string]*parseResult[any]),
+			errors: make([]*parseError, 0),
+		},
+		fields: make([]string, 0),
+	}
+
+	if r.err != nil {
+		parseRes.valid = false
+		parseRes.errors = []*parseError{validatorStateError(r.err)}
+		return parseRes
+	}
+
+	for _, section := range r.sections {
+		data, err := section.extract(req)
+		if err != nil {
+			parseRes.valid = false
+			parseRes.errors = append(parseRes.errors, err.withPath(section.source))
+			continue
+		}
+
+		res := section.schema.parse(data, section.schema.requestNumberFormat(req))
+
+		fieldErrors := make(map[*parseError]bool)
+		for _, name := range section.schema.fields {
+			fieldRes, ok := res.value[name]
+			if !ok {
+				continue
+			}
+			errs := make([]*parseError, len(fieldRes.errors))
+			for i, err := range fieldRes.errors {
+				fieldErrors[err] = true
+				errs[i] = err.withPath(section.source + "." + name)
+			}
+			parseRes.fields = append(parseRes.fields, name)
+			parseRes.value[name] = &parseResult[any]{valid: fieldRes.valid, value: fieldRes.value, errors: errs}
+			parseRes.errors = append(parseRes.errors, errs...)
+		}
+
+		// anything left over was raised by the section itself e.g. a refiner
+		for _, err := range res.errors {
+			if !fieldErrors[err] {
+				parseRes.errors = append(parseRes.errors, err.withPath(section.source))
+			}
+		}
+
+		if !res.valid {
+			parseRes.valid = false
+		}
+	}
+
+	return parseRes
+}
+
+func (s requestSection) extract(req *http.Request) (map[string]interface{}, *parseError) {
+	switch s.source {
+	case SourcePath:
+		data := make(map[string]interface{})
+		for _, name := range s.schema.fields {
+			if val := req.PathValue(name); val > "" {
+				data[name] = val
+			}
+		}
+		return data, nil
+
+	case SourceQuery:
+		return s.schema.readForm(req.URL.Query()), nil
+
+	case SourceHeader:
+		data := make(map[string]interface{})
+		for _, name := range s.schema.fields {
+			if vals := req.Header.Values(name); len(vals) > 0 {
+				data[name] = vals[0]
+			}
+		}
+		return data, nil
+
+	case SourceCookie:
+		data := make(map[string]interface{})
+		for _, name := range s.schema.fields {
+			if cookie, err := req.Cookie(name); err == nil {
+				data[name] = cookie.Value
+			}
+		}
+		return data, nil
+
+	case SourceBody:
+		if !hasBody(req) {
+			return make(map[string]interface{}), nil
+		}
+		return s.schema.decodeBody(req)
+	}
+
+	return nil, &parseError{message: "unknown request source"}
+}
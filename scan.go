@@ -0,0 +1,187 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+type ScanVerdict int
+
+const (
+	ScanClean ScanVerdict = iota
+	ScanInfected
+	ScanFailed
+)
+
+// DefaultScanTimeout is the time allowed for scanning a single uploaded file
+var DefaultScanTimeout = 30 * time.Second
+
+// FileScanner inspects the content of an uploaded file e.g. for malware
+type FileScanner interface {
+	Scan(ctx context.Context, name string, r io.Reader) (ScanVerdict, error)
+}
+
+// ScanWith runs each uploaded file through the scanner, files which are infected or cannot be scanned are reported as errors
+func ScanWith(scanner FileScanner, message ...string) parseOpt[[]File] {
+	return ScanWithTimeout(scanner, DefaultScanTimeout, message...)
+}
+
+// ScanWithTimeout is the same as ScanWith but allows the per file timeout to be set, scans are also stopped when the
+// request the file was uploaded with is cancelled
+func ScanWithTimeout(scanner FileScanner, timeout time.Duration, message ...string) parseOpt[[]File] {
+	return func(val *[]File) *parseError {
+		if val == nil {
+			return nil
+		}
+		inner := make([]error, 0)
+		for _, file := range *val {
+			if err := scanFile(scanner, timeout, file); err != nil {
+				inner = append(inner, err)
+			}
+		}
+		if len(inner) == 0 {
+			return nil
+		}
+		if len(message) > 0 {
+			return &parseError{message: message[0], inner: inner}
+		}
+		return &parseError{message: "file failed malware scan", inner: inner}
+	}
+}
+
+func scanFile(scanner FileScanner, timeout time.Duration, file File) error {
+	if file.Header == nil {
+		return errors.New("missing file header")
+	}
+
+	fp, err := file.Header.Open()
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", file.Header.Filename, err)
+	}
+	defer fp.Close()
+
+	ctx, cancel := context.WithTimeout(file.Context(), timeout)
+	defer cancel()
+
+	verdict, err := scanner.Scan(ctx, file.Header.Filename, fp)
+	switch {
+	case err != nil:
+		return fmt.Errorf("scanning %s: %w", file.Header.Filename, err)
+	case verdict == ScanInfected:
+		return fmt.Errorf("%s is infected", file.Header.Filename)
+	case verdict != ScanClean:
+		return fmt.Errorf("scanning %s failed", file.Header.Filename)
+	}
+	return nil
+}
+
+// ClamAVScanner scans files using the clamd INSTREAM command
+type ClamAVScanner struct {
+	Network   string
+	Address   string
+	ChunkSize int
+	dialer    net.Dialer
+}
+
+func NewClamAVScanner(network, address string) *ClamAVScanner {
+	return &ClamAVScanner{
+		Network:   network,
+		Address:   address,
+		ChunkSize: 32 * 1024,
+	}
+}
+
+func (s *ClamAVScanner) Scan(ctx context.Context, name string, r io.Reader) (ScanVerdict, error) {
+	conn, err := s.dialer.DialContext(ctx, s.Network, s.Address)
+	if err != nil {
+		return ScanFailed, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return ScanFailed, err
+		}
+	}
+
+	// make sure a cancelled context interrupts any blocked reads or writes
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return ScanFailed, err
+	}
+
+	chunkSize := s.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = 32 * 1024
+	}
+	buf := make([]byte, 4+chunkSize)
+	for {
+		n, err := r.Read(buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if _, err := conn.Write(buf[:4+n]); err != nil {
+				return ScanFailed, err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ScanFailed, err
+		}
+	}
+
+	// a zero length chunk terminates the stream
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return ScanFailed, err
+	}
+
+	reply, err := io.ReadAll(conn)
+	if err != nil && len(reply) == 0 {
+		return ScanFailed, err
+	}
+
+	return parseClamAVReply(reply)
+}
+
+func parseClamAVReply(reply []byte) (ScanVerdict, error) {
+	res := strings.TrimSpace(string(bytes.TrimRight(reply, "\x00")))
+	res = strings.TrimPrefix(res, "stream: ")
+	switch {
+	case res == "OK":
+		return ScanClean, nil
+	case strings.HasSuffix(res, " FOUND"):
+		return ScanInfected, nil
+	case strings.HasSuffix(res, " ERROR"):
+		return ScanFailed, errors.New(strings.TrimSuffix(res, " ERROR"))
+	default:
+		return ScanFailed, fmt.Errorf("unexpected clamd reply: %q", res)
+	}
+}
@@ -0,0 +1,208 @@
+package ursa
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"iter"
+)
+
+type streamConfig struct {
+	maxErrors int
+}
+
+type streamOpt func(c *streamConfig)
+
+// StopAfterErrors ends the stream once the given number of invalid records have been returned
+func StopAfterErrors(count int) streamOpt {
+	return func(c *streamConfig) {
+		c.maxErrors = count
+	}
+}
+
+// ParseStream validates each record in an NDJSON stream or a top level JSON array without reading the whole input into memory.
+// The key is the line number on which the record starts. A malformed stream yields a final invalid result and stops.
+func (o *objectValidator) ParseStream(r io.Reader, opts ...streamOpt) iter.Seq2[int, *objectParseResult] {
+	cfg := &streamConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(yield func(int, *objectParseResult) bool) {
+		if err := o.Error(); err != nil {
+			yield(0, invalidObjectResult(validatorStateError(err)))
+			return
+		}
+
+		numErrors := 0
+		emit := func(line int, res *objectParseResult) bool {
+			if !res.IsValid() {
+				numErrors++
+			}
+			if !yield(line, res) {
+				return false
+			}
+			return cfg.maxErrors <= 0 || numErrors < cfg.maxErrors
+		}
+
+		br := bufio.NewReader(r)
+		first, skipped, err := peekNonSpace(br)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			yield(skipped+1, invalidObjectResult(&parseError{message: "reading stream", inner: []error{err}}))
+			return
+		}
+
+		if first == '[' {
+			o.parseArrayStream(&lineTracker{r: br, line: skipped + 1}, emit)
+		} else {
+			o.parseLineStream(br, skipped, emit)
+		}
+	}
+}
+
+func (o *objectValidator) parseLineStream(br *bufio.Reader, lineNum int, emit func(int, *objectParseResult) bool) {
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			lineNum++
+			if len(bytes.TrimSpace(line)) > 0 {
+				if !emit(lineNum, o.parseRecord(line)) {
+					return
+				}
+			}
+		}
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			emit(lineNum+1, invalidObjectResult(&parseError{message: "reading stream", inner: []error{err}}))
+			return
+		}
+	}
+}
+
+func (o *objectValidator) parseRecord(data []byte) *objectParseResult {
+	unpacked, err := o.decodeJSON(data)
+	if err != nil {
+		return invalidObjectResult(err)
+	}
+	return o.Parse(unpacked)
+}
+
+func (o *objectValidator) parseArrayStream(tracker *lineTracker, emit func(int, *objectParseResult) bool) {
+	dec := json.NewDecoder(tracker)
+
+	if _, err := dec.Token(); err != nil {
+		emit(tracker.line, invalidObjectResult(&parseError{message: "unmarshalling JSON value", inner: []error{err}}))
+		return
+	}
+
+	count := 0
+	for dec.More() {
+		line := tracker.lineAt(dec.InputOffset())
+		if o.jsonLimits.maxArrayLength > 0 && count >= o.jsonLimits.maxArrayLength {
+			emit(line, invalidObjectResult(JSONArrayTooLongError.withPath("")))
+			return
+		}
+		count++
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			emit(line, invalidObjectResult(&parseError{message: "unmarshalling JSON value", inner: []error{err}}))
+			return
+		}
+		if !emit(line, o.parseRecord(raw)) {
+			return
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		emit(tracker.lineAt(dec.InputOffset()), invalidObjectResult(&parseError{message: "unmarshalling JSON value", inner: []error{err}}))
+		return
+	}
+
+	if o.jsonLimits.disallowTrailingData {
+		line := tracker.lineAt(dec.InputOffset())
+		if err := checkTrailingData(dec); err != nil {
+			emit(line, invalidObjectResult(err))
+		}
+	}
+}
+
+// peekNonSpace skips leading white space and returns the first significant byte along with the number of lines skipped
+func peekNonSpace(br *bufio.Reader) (byte, int, error) {
+	lines := 0
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, lines, err
+		}
+		if b == '\n' {
+			lines++
+		}
+		if !isJSONSpace(b) {
+			return b, lines, br.UnreadByte()
+		}
+	}
+}
+
+func isJSONSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+// lineTracker keeps the bytes which have been read but not yet counted so the line of a record
+// can be calculated from its offset, memory use is bounded by the size of a record plus read ahead
+type lineTracker struct {
+	r      io.Reader
+	buf    []byte
+	offset int64
+	line   int
+}
+
+func (t *lineTracker) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	t.buf = append(t.buf, p[:n]...)
+	return n, err
+}
+
+// lineAt returns the line of the first token at or after the offset, bytes before the offset are discarded
+func (t *lineTracker) lineAt(offset int64) int {
+	ix := int(offset - t.offset)
+	if ix < 0 || ix > len(t.buf) {
+		return t.line
+	}
+
+	t.line += bytes.Count(t.buf[:ix], []byte{'\n'})
+	t.buf = append(t.buf[:0], t.buf[ix:]...)
+	t.offset = offset
+
+	line := t.line
+	for _, b := range t.buf {
+		if b == '\n' {
+			line++
+		} else if !isJSONSpace(b) && b != ',' {
+			break
+		}
+	}
+	return line
+}
@@ -1,8 +1,15 @@
 package ursa
 
 import (
-	"net/mail"
 	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
 )
 
 // ursa is a zod inspired validation library for Go.
@@ -27,36 +34,150 @@ func String(opts ...any) genericValidator[string] {
 	return validatorFactory[string](opts...)
 }
 
+// LengthUnit selects how the length of a string is counted
+type LengthUnit int
+
+const (
+	// Runes counts Unicode code points so "José" has a length of 4
+	Runes LengthUnit = iota
+	// Graphemes counts user perceived characters so an emoji made of several code points has a length of 1
+	Graphemes
+)
+
+func (l LengthUnit) count(val string) int {
+	if l == Graphemes {
+		return uniseg.GraphemeClusterCount(val)
+	}
+	return utf8.RuneCountInString(val)
+}
+
 func MinLength(min int, message ...string) stringValidatorOpt {
+	return MinLengthIn(Runes, min, message...)
+}
+
+func MaxLength(max int, message ...string) stringValidatorOpt {
+	return MaxLengthIn(Runes, max, message...)
+}
+
+func MinLengthIn(unit LengthUnit, min int, message ...string) stringValidatorOpt {
 	return func(val *string) *parseError {
 		if val == nil {
 			return nil
 		}
-		if len(*val) < min {
+		if unit.count(*val) < min {
+			params := map[string]any{"min": min}
 			if len(message) > 0 {
-				return &parseError{message: message[0]}
+				return &parseError{message: message[0], params: params}
 			}
-			return &parseError{message: "string too short"}
+			return &parseError{message: "string too short", params: params}
 		}
 		return nil
 	}
 }
 
-func MaxLength(max int, message ...string) stringValidatorOpt {
+func MaxLengthIn(unit LengthUnit, max int, message ...string) stringValidatorOpt {
+	return func(val *string) *parseError {
+		if val == nil {
+			return nil
+		}
+		if unit.count(*val) > max {
+			params := map[string]any{"max": max}
+			if len(message) > 0 {
+				return &parseError{message: message[0], params: params}
+			}
+			return &parseError{message: "string too long", params: params}
+		}
+		return nil
+	}
+}
+
+// MaxBytes limits the UTF-8 encoded size e.g. to fit a database column
+func MaxBytes(max int, message ...string) stringValidatorOpt {
 	return func(val *string) *parseError {
 		if val == nil {
 			return nil
 		}
 		if len(*val) > max {
+			params := map[string]any{"maxBytes": max}
 			if len(message) > 0 {
-				return &parseError{message: message[0]}
+				return &parseError{message: message[0], params: params}
 			}
-			return &parseError{message: "string too long"}
+			return &parseError{message: "string too large", params: params}
 		}
 		return nil
 	}
 }
 
+// NormalizeNFC replaces the value with its canonical composition, options run in order so it should come before any length checks
+func NormalizeNFC() stringValidatorOpt {
+	return func(val *string) *parseError {
+		if val != nil {
+			*val = norm.NFC.String(*val)
+		}
+		return nil
+	}
+}
+
+// NormalizeNFKC also folds compatibility characters e.g. "ﬁ" becomes "fi" and full width digits become ASCII
+func NormalizeNFKC() stringValidatorOpt {
+	return func(val *string) *parseError {
+		if val != nil {
+			*val = norm.NFKC.String(*val)
+		}
+		return nil
+	}
+}
+
+// NoControlChars rejects control characters other than tab, carriage return and line feed
+func NoControlChars(message ...string) stringValidatorOpt {
+	return rejectRunes(func(r rune) bool {
+		return unicode.IsControl(r) && r != '\t' && r != '\r' && r != '\n'
+	}, "string contains control characters", message...)
+}
+
+// NoInvisibleChars rejects zero width and bidi formatting characters and blank fillers, zero width joiners are
+// allowed as they are needed by emoji sequences and some scripts
+func NoInvisibleChars(message ...string) stringValidatorOpt {
+	return rejectRunes(isInvisible, "string contains invisible characters", message...)
+}
+
+// PrintableOnly allows letters, marks, numbers, punctuation, symbols and the ASCII space
+func PrintableOnly(message ...string) stringValidatorOpt {
+	return rejectRunes(func(r rune) bool {
+		return !unicode.IsPrint(r)
+	}, "string contains non printable characters", message...)
+}
+
+func rejectRunes(reject func(r rune) bool, defaultMessage string, message ...string) stringValidatorOpt {
+	return func(val *string) *parseError {
+		if val == nil {
+			return nil
+		}
+		if !utf8.ValidString(*val) {
+			return &parseError{message: "string is not valid UTF-8"}
+		}
+		for _, r := range *val {
+			if reject(r) {
+				if len(message) > 0 {
+					return &parseError{message: message[0]}
+				}
+				return &parseError{message: defaultMessage}
+			}
+		}
+		return nil
+	}
+}
+
+func isInvisible(r rune) bool {
+	switch r {
+	case '\u200c', '\u200d':
+		return false
+	case '\u115f', '\u1160', '\u3164', '\uffa0', '\u2800', '\u180e':
+		return true
+	}
+	return unicode.Is(unicode.Cf, r)
+}
+
 func Matches(patt string, message ...string) stringValidatorOpt {
 	re, err := regexp.Compile(patt)
 	return func(val *string) *parseError {
@@ -76,32 +197,175 @@ func Matches(patt string, message ...string) stringValidatorOpt {
 	}
 }
 
-func Email(message ...string) stringValidatorOpt {
+func Enum(values ...string) stringValidatorOpt {
 	return func(val *string) *parseError {
 		if val == nil {
 			return nil
 		}
-		_, err := mail.ParseAddress(*val)
-		if err != nil {
-			if len(message) > 0 {
-				return &parseError{message: message[0], inner: []error{err}}
+		for _, v := range values {
+			if v == *val {
+				return nil
 			}
-			return &parseError{message: "invalid email address", inner: []error{err}}
 		}
-		return nil
+		return &parseError{
+			message: "value not found in enum",
+			inner:   []error{},
+			code:    "invalid_enum",
+			params:  map[string]any{"value": *val, "allowed": values},
+		}
 	}
 }
 
-func Enum(values ...string) stringValidatorOpt {
+// codeOpt configures the validators for standard codes such as CountryCode
+type codeOpt func(c *codeConfig)
+
+type codeConfig struct {
+	canonical bool
+	alpha3    bool
+	message   []string
+}
+
+// Canonical accepts any case and known aliases and rewrites the value in its canonical form e.g. "uk" becomes "GB",
+// without it the value must already be canonical
+func Canonical() codeOpt {
+	return func(c *codeConfig) {
+		c.canonical = true
+	}
+}
+
+// Alpha3 uses the three letter codes for countries e.g. GBR and languages e.g. eng
+func Alpha3() codeOpt {
+	return func(c *codeConfig) {
+		c.alpha3 = true
+	}
+}
+
+// codeValidator checks the value against its canonical form, canonicalize returns false for unknown codes
+func codeValidator(code, defaultMessage string, canonicalize func(val string, c *codeConfig) (string, bool), opts []any) stringValidatorOpt {
+	cfg := &codeConfig{}
+	for _, opt := range opts {
+		switch opt := opt.(type) {
+		case codeOpt:
+			opt(cfg)
+		case string:
+			cfg.message = []string{opt}
+		}
+	}
 	return func(val *string) *parseError {
 		if val == nil {
 			return nil
 		}
-		for _, v := range values {
-			if v == *val {
-				return nil
+		canonical, ok := canonicalize(strings.TrimSpace(*val), cfg)
+		if !ok || (!cfg.canonical && canonical != *val) {
+			message := defaultMessage
+			if len(cfg.message) > 0 {
+				message = cfg.message[0]
 			}
+			return &parseError{message: message, code: code, params: map[string]any{"value": *val}}
+		}
+		if cfg.canonical {
+			*val = canonical
+		}
+		return nil
+	}
+}
+
+// TimeZone checks for an IANA time zone name e.g. Europe/London using the zone database of the system,
+// import time/tzdata to check against the snapshot embedded in the binary instead
+func TimeZone(opts ...any) stringValidatorOpt {
+	return codeValidator("invalid_time_zone", "invalid time zone", canonicalTimeZone, opts)
+}
+
+func canonicalTimeZone(val string, c *codeConfig) (string, bool) {
+	candidates := []string{val}
+	if c.canonical {
+		candidates = append(candidates, strings.ToUpper(val), titleTimeZone(val))
+	}
+	for _, name := range candidates {
+		// LoadLocation treats these as the zone of the machine rather than a name
+		if name == "" || name == "Local" {
+			continue
+		}
+		if _, err := time.LoadLocation(name); err == nil {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// titleTimeZone capitalises each word of a zone name e.g. america/new_york becomes America/New_York
+func titleTimeZone(val string) string {
+	out := []rune(strings.ToLower(val))
+	for i, r := range out {
+		if i == 0 || strings.ContainsRune("/_-", out[i-1]) {
+			out[i] = unicode.ToUpper(r)
+		}
+	}
+	return string(out)
+}
+
+// LanguageTag checks for a BCP 47 language tag e.g. en-GB or zh-Hant-TW
+func LanguageTag(opts ...any) stringValidatorOpt {
+	return codeValidator("invalid_language_tag", "invalid language tag", func(val string, c *codeConfig) (string, bool) {
+		tag, err := language.Parse(val)
+		if err != nil || tag == language.Und {
+			return "", false
 		}
-		return &parseError{message: "value not found in enum", inner: []error{}}
+		return tag.String(), true
+	}, opts)
+}
+
+// LanguageCode checks for an ISO 639 language code, two letter codes are canonical unless Alpha3 is given
+func LanguageCode(opts ...any) stringValidatorOpt {
+	return codeValidator("invalid_language", "invalid language code", func(val string, c *codeConfig) (string, bool) {
+		base, err := language.ParseBase(val)
+		if err != nil || base.String() == "und" {
+			return "", false
+		}
+		if c.alpha3 {
+			return base.ISO3(), true
+		}
+		return base.String(), true
+	}, opts)
+}
+
+// countryAliases are exceptionally reserved codes which are commonly used in place of the assigned code
+var countryAliases = map[string]string{"UK": "GB", "EL": "GR"}
+
+var countryAlpha2 = func() map[string]string {
+	codes := make(map[string]string, len(countryCodes))
+	for alpha2, alpha3 := range countryCodes {
+		codes[alpha3] = alpha2
 	}
+	return codes
+}()
+
+// CountryCode checks for an ISO 3166-1 country code, alpha-2 codes are canonical unless Alpha3 is given
+func CountryCode(opts ...any) stringValidatorOpt {
+	return codeValidator("invalid_country", "invalid country code", func(val string, c *codeConfig) (string, bool) {
+		code := strings.ToUpper(val)
+		if alias, ok := countryAliases[code]; ok {
+			code = alias
+		}
+		if alpha2, ok := countryAlpha2[code]; ok {
+			code = alpha2
+		}
+		alpha3, ok := countryCodes[code]
+		if !ok {
+			return "", false
+		}
+		if c.alpha3 {
+			return alpha3, true
+		}
+		return code, true
+	}, opts)
+}
+
+// CurrencyCode checks for an active ISO 4217 currency code e.g. EUR
+func CurrencyCode(opts ...any) stringValidatorOpt {
+	return codeValidator("invalid_currency", "invalid currency code", func(val string, c *codeConfig) (string, bool) {
+		code := strings.ToUpper(val)
+		_, ok := currencyMinorUnits[code]
+		return code, ok
+	}, opts)
 }
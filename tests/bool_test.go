@@ -0,0 +1,137 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBool(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Bool()
+	for val, expected := range map[any]bool{
+		true: true, "true": true, "Yes": true, "Y": true, "on": true, "1": true, 1: true, json.Number("1"): true,
+		false: false, "FALSE": false, "no": false, "n": false, "off": false, "0": false, 0: false,
+	} {
+		res := v.Parse(val)
+		assert.True(res.IsValid(), val)
+		assert.Equal(expected, res.Get(), val)
+	}
+
+	for _, val := range []any{"", "maybe", 2, 0.5} {
+		errs := v.Parse(val).Errors()
+		assert.Equal(1, len(errs), val)
+		assert.ErrorIs(errs[0], u.InvalidTypeError, val)
+	}
+
+	v = u.Bool(u.WithTruthy(append(u.DefaultTruthy, "ja")...), u.WithFalsy("nein"), u.True())
+	assert.True(v.Parse("JA").IsValid())
+	assert.True(v.Parse("yes").IsValid())
+	errs := v.Parse("nein").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("value should be true", errs[0].Error())
+	assert.False(v.Parse("no").IsValid())
+}
+
+func TestBoolCheckbox(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Bool(u.Checkbox())
+	res := v.Parse(nil)
+	assert.True(res.IsValid())
+	assert.False(res.Get())
+	res = v.Parse("")
+	assert.True(res.IsValid())
+	assert.False(res.Get())
+	assert.True(v.Parse("on").Get())
+
+	schema := u.Object().
+		Bool("newsletter", u.Checkbox()).
+		Bool("terms", u.Checkbox(), u.True("please accept the terms"))
+
+	form := url.Values{"terms": {"on"}}
+	req, _ := http.NewRequest(http.MethodPost, "/prefs", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	objRes := schema.Parse(req)
+	assert.True(objRes.IsValid())
+	assert.False(objRes.GetBool("newsletter"))
+	assert.True(objRes.GetBool("terms"))
+
+	req, _ = http.NewRequest(http.MethodPost, "/prefs", strings.NewReader(""))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	objRes = schema.Parse(req)
+	assert.False(objRes.IsValid())
+	assert.Equal("please accept the terms", objRes.GetError("terms"))
+}
+
+func TestTriStateBool(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.TriStateBool()
+	for val, expected := range map[any]u.OptionalBool{
+		"yes": u.BoolTrue, true: u.BoolTrue, "no": u.BoolFalse, false: u.BoolFalse, "": u.BoolUnset,
+	} {
+		res := v.Parse(val)
+		assert.True(res.IsValid(), val)
+		assert.Equal(expected, res.Get(), val)
+	}
+	res := v.Parse(nil)
+	assert.True(res.IsValid())
+	assert.Equal(u.BoolUnset, res.Get())
+	assert.False(v.Parse("maybe").IsValid())
+
+	assert.False(u.TriStateBool(u.Required()).Parse(nil).IsValid())
+
+	b, ok := u.BoolFalse.Bool()
+	assert.False(b)
+	assert.True(ok)
+	assert.Nil(u.BoolUnset.Ptr())
+	assert.True(*u.BoolTrue.Ptr())
+
+	type prefs struct {
+		Marketing u.OptionalBool `json:"marketing"`
+		Digest    u.OptionalBool `json:"digest"`
+	}
+	schema := u.Object().
+		TriStateBool("marketing").
+		TriStateBool("digest")
+
+	objRes := schema.Parse([]byte(`{"marketing":false}`))
+	assert.True(objRes.IsValid())
+	assert.False(objRes.GetBool("marketing"))
+
+	p := prefs{}
+	assert.NoError(objRes.Unmarshal(&p))
+	assert.Equal(prefs{Marketing: u.BoolFalse, Digest: u.BoolUnset}, p)
+
+	data, err := json.Marshal(p)
+	assert.NoError(err)
+	assert.Equal(`{"marketing":false,"digest":null}`, string(data))
+
+	p = prefs{}
+	assert.NoError(json.Unmarshal([]byte(`{"marketing":true,"digest":null}`), &p))
+	assert.Equal(prefs{Marketing: u.BoolTrue, Digest: u.BoolUnset}, p)
+}
@@ -0,0 +1,146 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"testing"
+	"time"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarDate(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Date(u.NotBeforeDate(u.CalendarDate{Year: 2024, Month: time.January, Day: 1}))
+
+	res := v.Parse("2024-02-29")
+	assert.True(res.IsValid())
+	assert.Equal(u.CalendarDate{Year: 2024, Month: time.February, Day: 29}, res.Get())
+	assert.Equal("2024-02-29", res.Get().String())
+
+	// the date is taken in the location of the time
+	tokyo := time.FixedZone("JST", 9*60*60)
+	res = v.Parse(time.Date(2024, 3, 1, 1, 0, 0, 0, tokyo))
+	assert.Equal(u.CalendarDate{Year: 2024, Month: time.March, Day: 1}, res.Get())
+
+	errs := v.Parse("2023-02-29").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("invalid_date", errs[0].Code())
+
+	errs = v.Parse("2023-12-31").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("date_too_early", errs[0].Code())
+
+	errs = v.Parse(20240101).Errors()
+	assert.Equal(1, len(errs))
+	assert.ErrorIs(errs[0], u.InvalidTypeError)
+
+	d := u.CalendarDate{}
+	assert.NoError(d.UnmarshalText([]byte("2024-12-25")))
+	assert.Equal(time.December, d.Month)
+	text, _ := d.MarshalText()
+	assert.Equal("2024-12-25", string(text))
+}
+
+func TestCalendarDateRelative(t *testing.T) {
+	assert := assert.New(t)
+
+	clock := u.WithClock(u.ClockFunc(func() time.Time {
+		return time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC)
+	}))
+
+	v := u.Date(u.MinAge(18), u.InPast(), clock)
+	assert.True(v.Parse("2006-06-15").IsValid())
+	errs := v.Parse("2006-06-16").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("too_young", errs[0].Code())
+
+	// today is neither in the past nor in the future
+	assert.True(u.Date(u.InPast(), clock).Parse("2024-06-15").IsValid())
+	assert.False(u.Date(u.InFuture(), clock).Parse("2024-06-15").IsValid())
+	assert.True(u.Date(u.InFuture(), clock).Parse("2024-06-16").IsValid())
+	assert.False(u.Date(u.MaxAge(100), clock).Parse("1900-01-01").IsValid())
+
+	// 2024-06-15 is a Saturday
+	errs = u.Date(u.BusinessDay(nil)).Parse("2024-06-15").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("not_business_day", errs[0].Code())
+	assert.True(u.Date(u.Weekday(time.Saturday)).Parse("2024-06-15").IsValid())
+}
+
+func TestTimeOfDay(t *testing.T) {
+	assert := assert.New(t)
+
+	open := u.ClockTime{Hour: 9}
+	close := u.ClockTime{Hour: 17, Minute: 30}
+	v := u.TimeOfDay(u.TimeBetween(open, close))
+
+	for s, expected := range map[string]u.ClockTime{
+		"09:00":    {Hour: 9},
+		"17:30":    {Hour: 17, Minute: 30},
+		"12:15:30": {Hour: 12, Minute: 15, Second: 30},
+		"1:45pm":   {Hour: 13, Minute: 45},
+	} {
+		res := v.Parse(s)
+		assert.True(res.IsValid(), s)
+		assert.Equal(expected, res.Get(), s)
+	}
+
+	errs := v.Parse("17:31").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("time_out_of_range", errs[0].Code())
+	assert.Equal("09:00", errs[0].Params()["start"])
+
+	errs = v.Parse("25:00").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("invalid_time_of_day", errs[0].Code())
+
+	// ranges past midnight wrap
+	v = u.TimeOfDay(u.TimeBetween(u.ClockTime{Hour: 22}, u.ClockTime{Hour: 2}))
+	assert.True(v.Parse("23:00").IsValid())
+	assert.True(v.Parse("01:59").IsValid())
+	assert.False(v.Parse("12:00").IsValid())
+
+	assert.Equal(9*time.Hour+30*time.Minute, u.ClockTime{Hour: 9, Minute: 30}.SinceMidnight())
+	assert.Equal("12:15:30", u.ClockTime{Hour: 12, Minute: 15, Second: 30}.String())
+}
+
+func TestCalendarObject(t *testing.T) {
+	assert := assert.New(t)
+
+	type openingHours struct {
+		Day    u.CalendarDate `json:"day"`
+		Opens  u.ClockTime    `json:"opens"`
+		Closes u.ClockTime    `json:"closes"`
+	}
+
+	v := u.Object().
+		Date("day", u.Required()).
+		TimeOfDay("opens", u.Required()).
+		TimeOfDay("closes", u.Required())
+
+	res := v.Parse([]byte(`{"day":"2024-12-24","opens":"08:00","closes":"13:00"}`))
+	assert.True(res.IsValid())
+
+	h := openingHours{}
+	assert.NoError(res.Unmarshal(&h))
+	assert.Equal(24, h.Day.Day)
+	assert.Equal(u.ClockTime{Hour: 13}, h.Closes)
+	assert.Equal(time.Date(2024, 12, 24, 8, 0, 0, 0, time.UTC), h.Opens.On(h.Day, time.UTC))
+}
@@ -0,0 +1,112 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"strings"
+	"testing"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+type priceRow struct {
+	SKU      string  `json:"sku"`
+	Price    float64 `json:"price"`
+	InStock  bool    `json:"inStock"`
+	Quantity int     `json:"quantity"`
+}
+
+func TestValidateCSV(t *testing.T) {
+	assert := assert.New(t)
+
+	schema := u.Object().
+		String("sku", u.MinLength(3), u.Required()).
+		Float64("price", u.Min(0)).
+		Bool("inStock", u.WithDefault(false)).
+		Int("quantity", u.Min(1))
+
+	data := `SKU,Unit Price,inStock,Quantity
+abc,1.50,true,10
+x,2.00,false,1
+def,-1,maybe,0
+ghi,3,,5
+`
+
+	report := u.ValidateCSV(strings.NewReader(data), schema, u.CSVAlias("Unit Price", "price"))
+
+	rows := make([]priceRow, 0)
+	rowNums := make([]int, 0)
+	for row, res := range report.Rows() {
+		rowNums = append(rowNums, row)
+		p := priceRow{}
+		assert.NoError(res.Unmarshal(&p))
+		rows = append(rows, p)
+	}
+	assert.NoError(report.Err())
+
+	assert.Equal([]int{2, 5}, rowNums)
+	assert.Equal([]priceRow{
+		{SKU: "abc", Price: 1.5, InStock: true, Quantity: 10},
+		{SKU: "ghi", Price: 3, InStock: false, Quantity: 5},
+	}, rows)
+
+	assert.Equal([]u.CSVRowError{
+		{Row: 3, Column: "SKU", Message: "string too short"},
+		{Row: 4, Column: "Unit Price", Message: "number too small"},
+		{Row: 4, Column: "inStock", Message: "invalid type"},
+		{Row: 4, Column: "Quantity", Message: "number too small"},
+	}, report.Errors())
+}
+
+func TestValidateCSVTabs(t *testing.T) {
+	assert := assert.New(t)
+
+	schema := u.Object().
+		String("sku", u.Required()).
+		Int("quantity")
+
+	data := "sku\tquantity\nabc\t1\n\t2\n"
+	report := u.ValidateCSV(strings.NewReader(data), schema, u.CSVComma('\t'))
+
+	count := 0
+	for range report.Rows() {
+		count++
+	}
+	assert.Equal(1, count)
+	assert.Equal([]u.CSVRowError{{Row: 3, Column: "sku", Message: "missing required property"}}, report.Errors())
+}
+
+func TestValidateCSVMalformed(t *testing.T) {
+	assert := assert.New(t)
+
+	schema := u.Object().String("sku", u.Required())
+
+	data := "sku\na\"b\nabc\n"
+	report := u.ValidateCSV(strings.NewReader(data), schema)
+
+	rowNums := make([]int, 0)
+	for row := range report.Rows() {
+		rowNums = append(rowNums, row)
+	}
+	assert.NoError(report.Err())
+	assert.Equal([]int{3}, rowNums)
+	if assert.Equal(1, len(report.Errors())) {
+		assert.Equal(2, report.Errors()[0].Row)
+		assert.Equal(`bare " in non-quoted-field`, report.Errors()[0].Message)
+	}
+}
@@ -17,6 +17,7 @@ package tests
 // along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -54,18 +55,272 @@ func TestDate(t *testing.T) {
 	assert.Equal(errs[0].Error(), "date is too late")
 }
 
-func TestDateMissingParser(t *testing.T) {
+func TestDateDefaultFormats(t *testing.T) {
 	assert := assert.New(t)
 
-	dt := time.Now()
-	szdt := dt.Format(time.RFC3339)
+	v := u.Time()
 
-	v := u.Time(
-		u.NotBefore(dt.Add(-1*time.Hour)),
-		u.NotAfter(dt.Add(1*time.Hour)),
-	)
+	for _, s := range []string{
+		"2024-03-01T10:30:00Z",
+		"2024-03-01T10:30:00.123456789+01:00",
+		"2024-03-01T10:30:00",
+		"2024-03-01T10:30",
+		"2024-03-01",
+	} {
+		res := v.Parse(s)
+		assert.True(res.IsValid(), s)
+	}
 
-	errs := v.Parse(szdt).Errors()
+	res := v.Parse("2024-03-01T10:30")
+	assert.Equal(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC), res.Get())
+
+	errs := v.Parse("01/03/2024").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("invalid time", errs[0].Error())
+
+	errs = v.Parse(1709288400).Errors()
 	assert.Equal(1, len(errs))
-	assert.ErrorIs(errs[0], u.MissingTransformerError)
+	assert.ErrorIs(errs[0], u.InvalidTypeError)
+}
+
+func TestDateFormats(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Time(u.WithTimeFormats("02/01/2006", "02/01/2006 15:04"))
+
+	res := v.Parse("01/03/2024 10:30")
+	assert.True(res.IsValid())
+	assert.Equal(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC), res.Get())
+
+	res = v.Parse("2024-03-01")
+	assert.False(res.IsValid())
+}
+
+func TestUnsupportedOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	// options for another type of validator are errors rather than being ignored
+	assert.EqualError(u.String(u.WithTimeFormat(time.RFC1123)).Error(), "unsupported option ursa.timeOpt for string validator")
+	assert.Error(u.String(u.Checkbox()).Error())
+	assert.Error(u.Int(u.MinLength(3)).Error())
+	assert.Error(u.LatLng(u.PortRange(1, 2)).Error())
+	errs := u.String(u.Checkbox()).Parse("abc").Errors()
+	assert.ErrorIs(errs[0], u.InvalidValidatorStateError)
+	assert.Equal("invalid_validator", errs[0].Code())
+	assert.Contains(errs[0].Error(), "unsupported option")
+
+	res := u.Object().String("name", u.Checkbox()).Parse(map[string]any{"name": "abc"})
+	assert.Contains(res.GetError("name"), "unsupported option")
+
+	assert.NoError(u.Time(u.WithTimeFormat(time.RFC1123)).Error())
+	assert.NoError(u.Bool(u.Checkbox()).Error())
+}
+
+func TestDateUnix(t *testing.T) {
+	assert := assert.New(t)
+
+	expected := time.Date(2024, 3, 1, 10, 20, 0, 0, time.UTC)
+
+	v := u.Time(u.UnixSeconds())
+	for _, val := range []any{int64(1709288400), 1709288400.0, json.Number("1709288400"), "1709288400"} {
+		res := v.Parse(val)
+		assert.True(res.IsValid(), val)
+		assert.True(expected.Equal(res.Get()), val)
+	}
+
+	res := v.Parse(1709288400.5)
+	assert.True(expected.Add(500 * time.Millisecond).Equal(res.Get()))
+
+	v = u.Time(u.UnixMillis())
+	res = v.Parse(int64(1709288400123))
+	assert.True(expected.Add(123 * time.Millisecond).Equal(res.Get()))
+
+	res = v.Parse("1e400")
+	assert.False(res.IsValid())
+}
+
+func TestDateLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	london, err := time.LoadLocation("Europe/London")
+	if !assert.NoError(err) {
+		return
+	}
+
+	v := u.Time(u.AssumeLocation(london))
+
+	// British Summer Time
+	res := v.Parse("2024-07-01T10:30")
+	assert.True(res.IsValid())
+	assert.True(time.Date(2024, 7, 1, 9, 30, 0, 0, time.UTC).Equal(res.Get()))
+
+	// an explicit zone wins
+	res = v.Parse("2024-07-01T10:30:00Z")
+	assert.True(time.Date(2024, 7, 1, 10, 30, 0, 0, time.UTC).Equal(res.Get()))
+
+	v = u.Time(u.AssumeLocation(london), u.InLocation(time.UTC))
+	res = v.Parse("2024-07-01T10:30")
+	assert.Equal(time.UTC, res.Get().Location())
+	assert.Equal(9, res.Get().Hour())
+
+	v = u.Time(u.InLocation(london), u.NotAfter(time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)))
+	res = v.Parse(time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC))
+	assert.True(res.IsValid())
+	assert.Equal(london, res.Get().Location())
+}
+
+func TestDateObject(t *testing.T) {
+	assert := assert.New(t)
+
+	london, err := time.LoadLocation("Europe/London")
+	if !assert.NoError(err) {
+		return
+	}
+
+	type booking struct {
+		StartsAt time.Time `json:"startsAt"`
+	}
+
+	v := u.Object().Time("startsAt", u.AssumeLocation(london), u.InLocation(time.UTC))
+
+	res := v.Parse([]byte(`{"startsAt":"2024-07-01T10:30"}`))
+	assert.True(res.IsValid())
+
+	b := booking{}
+	err = res.Unmarshal(&b)
+	assert.NoError(err)
+	assert.True(time.Date(2024, 7, 1, 9, 30, 0, 0, time.UTC).Equal(b.StartsAt))
+}
+
+func TestDateRelative(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := u.WithClock(u.ClockFunc(func() time.Time { return now }))
+
+	v := u.Time(clock, u.InPast())
+	assert.True(v.Parse(now.Add(-time.Minute)).IsValid())
+	errs := v.Parse(now.Add(time.Minute)).Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("not_in_past", errs[0].Code())
+
+	v = u.Time(clock, u.InFuture("must be later"))
+	assert.True(v.Parse(now.Add(time.Minute)).IsValid())
+	errs = v.Parse(now).Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("must be later", errs[0].Error())
+
+	v = u.Time(clock, u.WithinLast(24*time.Hour))
+	assert.True(v.Parse(now.Add(-23 * time.Hour)).IsValid())
+	assert.False(v.Parse(now.Add(-25 * time.Hour)).IsValid())
+	assert.False(v.Parse(now.Add(time.Hour)).IsValid())
+
+	v = u.Time(clock, u.WithinNext(14*24*time.Hour))
+	assert.True(v.Parse("2024-03-10").IsValid())
+	assert.False(v.Parse("2024-03-20").IsValid())
+
+	// the clock is read on every parse
+	now = now.Add(48 * time.Hour)
+	assert.True(v.Parse("2024-03-10").IsValid())
+	assert.False(v.Parse("2024-03-02").IsValid())
+}
+
+func TestDateAge(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	v := u.Time(u.WithClock(u.ClockFunc(func() time.Time { return now })), u.MinAge(18), u.MaxAge(120))
+
+	assert.True(v.Parse("2006-03-01").IsValid())
+	errs := v.Parse("2006-03-02").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("too_young", errs[0].Code())
+	assert.Equal(18, errs[0].Params()["minAge"])
+
+	// a leap day birthday is reached on 1 March
+	now = time.Date(2022, 2, 28, 9, 0, 0, 0, time.UTC)
+	assert.False(v.Parse("2004-02-29").IsValid())
+	now = time.Date(2022, 3, 1, 9, 0, 0, 0, time.UTC)
+	assert.True(v.Parse("2004-02-29").IsValid())
+	now = time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	errs = v.Parse("1900-01-01").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("too_old", errs[0].Code())
+}
+
+func TestDateCalendar(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Time(u.Weekday(time.Saturday, time.Sunday))
+	assert.True(v.Parse("2024-03-02").IsValid())
+	errs := v.Parse("2024-03-04").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("invalid_weekday", errs[0].Code())
+	assert.Equal([]string{"Saturday", "Sunday"}, errs[0].Params()["weekdays"])
+
+	holidays := u.NewHolidayList(time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC))
+	v = u.Time(u.BusinessDay(holidays))
+	assert.True(v.Parse("2024-12-24").IsValid())
+	assert.False(v.Parse("2024-12-25").IsValid())
+	assert.False(v.Parse("2024-12-28").IsValid())
+
+	v = u.Time(u.BusinessDay(u.HolidayCalendarFunc(func(date time.Time) bool {
+		return date.Month() == time.January && date.Day() == 1
+	})))
+	errs = v.Parse("2025-01-01").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("not_business_day", errs[0].Code())
+
+	assert.True(u.Time(u.BusinessDay(nil)).Parse("2025-01-01").IsValid())
+}
+
+func TestDateFields(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Object().
+		Time("checkIn", u.Required()).
+		Time("checkOut", u.Required(), u.NotBeforeField("checkIn")).
+		Time("paidAt", u.NotAfterField("checkIn"))
+
+	res := v.Parse([]byte(`{"checkIn":"2024-03-01","checkOut":"2024-03-04","paidAt":"2024-02-20"}`))
+	assert.True(res.IsValid())
+
+	res = v.Parse([]byte(`{"checkIn":"2024-03-01","checkOut":"2024-02-28","paidAt":"2024-03-02"}`))
+	assert.False(res.IsValid())
+	errs := res.GetField("checkOut").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("before_field", errs[0].Code())
+	assert.Equal("checkIn", errs[0].Params()["field"])
+	errs = res.GetField("paidAt").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("after_field", errs[0].Code())
+	assert.Equal(2, len(res.Errors()))
+
+	// an invalid field isn't compared
+	res = v.Parse([]byte(`{"checkIn":"soon","checkOut":"2024-02-28"}`))
+	assert.Equal(0, len(res.GetField("checkOut").Errors()))
+
+	// there is nothing to compare with outside an object
+	assert.ErrorIs(u.Time(u.NotBeforeField("checkIn")).Error(), u.FieldOptionOutsideObjectError)
+	assert.False(u.Time(u.NotAfterField("checkIn")).Parse("2024-03-01").IsValid())
+	assert.ErrorIs(u.Date(u.NotBeforeField("checkIn")).Error(), u.FieldOptionOutsideObjectError)
+
+	// the other field may be declared later but must exist and have the same type
+	assert.NoError(u.Object().Time("end", u.NotBeforeField("start")).Time("start").Error())
+	v = u.Object().Time("start").Time("end", u.NotBeforeField("strat"))
+	assert.ErrorContains(v.Error(), `"strat" which is not declared`)
+	res = v.Parse([]byte(`{"start":"2024-03-01","end":"2024-02-01"}`))
+	assert.False(res.IsValid())
+	assert.ErrorIs(res.Errors()[0], u.InvalidValidatorStateError)
+	assert.ErrorContains(u.Object().Date("start").Time("end", u.NotBeforeField("start")).Error(), "can't be compared")
+
+	// dates are compared as well as times
+	v = u.Object().
+		Date("checkIn", u.Required()).
+		Date("checkOut", u.Required(), u.NotBeforeField("checkIn"))
+	assert.True(v.Parse([]byte(`{"checkIn":"2024-03-01","checkOut":"2024-03-01"}`)).IsValid())
+	res = v.Parse([]byte(`{"checkIn":"2024-03-01","checkOut":"2024-02-29"}`))
+	assert.False(res.IsValid())
+	assert.Equal("before_field", res.GetField("checkOut").Errors()[0].Code())
 }
@@ -0,0 +1,95 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"math/big"
+	"testing"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimal(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Decimal(
+		u.Min("0.01"),
+		u.Max("99999999999999999999.99"),
+		u.MaxDecimalPlaces(2),
+		u.MaxDigits(22),
+		u.MultipleOf("0.05"))
+
+	res := v.Parse("12345678901234567890.15")
+	assert.True(res.IsValid())
+	assert.Equal("12345678901234567890.15", res.Get().FloatString(2))
+
+	res = v.Parse(0.1)
+	assert.True(res.IsValid())
+	assert.Equal(0, res.Get().Cmp(big.NewRat(1, 10)))
+
+	errs := v.Parse("0.001").Errors()
+	assert.Equal([]string{"number too small", "number has too many decimal places", "number is not a multiple of 0.05"}, messages(errs))
+
+	errs = v.Parse("100000000000000000000").Errors()
+	assert.Equal([]string{"number too large"}, messages(errs))
+
+	assert.False(v.Parse("abc").IsValid())
+	assert.False(v.Parse("1/3").IsValid())
+}
+
+func TestDecimalObject(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Object().
+		Decimal("amount", u.MaxDecimalPlaces(2), u.Required()).
+		BigInt("total", u.Min(0))
+
+	res := v.Parse([]byte(`{ "amount": 0.10, "total": 123456789012345678901234567890 }`))
+	assert.True(res.IsValid())
+	assert.Equal("0.1", res.GetString("amount"))
+	assert.Equal("123456789012345678901234567890", res.GetField("total").Get().(*big.Int).String())
+
+	tgt := struct {
+		Amount *big.Rat `json:"amount"`
+		Total  *big.Int `json:"total"`
+	}{}
+	assert.NoError(res.Unmarshal(&tgt))
+	assert.Equal("0.10", tgt.Amount.FloatString(2))
+
+	res = v.Parse([]byte(`{ "amount": "1.005", "total": 1.5 }`))
+	assert.False(res.IsValid())
+	assert.Equal("number has too many decimal places", res.GetError("amount"))
+	assert.False(res.IsFieldValid("total"))
+}
+
+func TestExactBounds(t *testing.T) {
+	assert := assert.New(t)
+
+	// these values are indistinguishable as float64
+	v := u.Int64(u.Max(int64(9007199254740993)))
+	assert.True(v.Parse(int64(9007199254740993)).IsValid())
+	assert.False(v.Parse(int64(9007199254740994)).IsValid())
+}
+
+func messages[E error](errs []E) []string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return msgs
+}
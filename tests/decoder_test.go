@@ -0,0 +1,122 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecoders(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Object().
+		String("Name", u.MinLength(5, "String should be at least 5 characters")).
+		Int("Count", u.Min(1)).
+		Object("Address", u.Object().String("City", u.Required()))
+
+	testCases := []struct {
+		name        string
+		contentType string
+		body        []byte
+	}{
+		{"xml", "application/xml", []byte(`<item Name="abcdef"><Count>5</Count><Address><City>Leeds</City></Address></item>`)},
+		{"xml suffix", "application/vnd.partner+xml", []byte(`<item><Name>abcdef</Name><Count>5</Count><Address><City>Leeds</City></Address></item>`)},
+		{"json suffix", "application/vnd.api+json", []byte(`{ "Name": "abcdef", "Count": 5, "Address": { "City": "Leeds" } }`)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "http://localhost:8080/upload", bytes.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+
+			res := v.Parse(req)
+			assert.Equal(0, len(res.Errors()))
+			assert.True(res.IsAllValid())
+			assert.Equal("abcdef", res.GetString("Name"))
+			assert.Equal(5, res.GetInt("Count"))
+
+			res = v.ParseFormat(tc.body, tc.contentType)
+			assert.True(res.IsAllValid())
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "http://localhost:8080/upload", bytes.NewReader([]byte("Name")))
+		req.Header.Set("Content-Type", "text/plain")
+
+		res := v.Parse(req)
+		assert.False(res.IsValid())
+		assert.ErrorIs(res.Errors()[0], u.UnsupportedContentTypeError)
+	})
+}
+
+func TestWithFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Object(u.WithFormat(u.FormatXML)).
+		String("Name", u.MinLength(5, "String should be at least 5 characters")).
+		Int("Count")
+
+	res := v.Parse([]byte(`<item><Name>abc</Name><Count>5</Count></item>`))
+	assert.False(res.IsValid())
+	assert.Equal("String should be at least 5 characters", res.GetError("Name"))
+	assert.Equal(5, res.GetField("Count").Get())
+}
+
+func TestDecodeDepth(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Object().String("Name")
+
+	// deeper than the default limit, the XML decoder would otherwise recurse once per element
+	deepXML := []byte(strings.Repeat("<a>", 20000) + strings.Repeat("</a>", 20000))
+	res := v.ParseFormat(deepXML, u.FormatXML)
+	assert.False(res.IsValid())
+	assert.ErrorIs(res.Errors()[0], u.DocumentTooDeepError)
+
+	deepJSON := []byte(`{"a":` + strings.Repeat("[", 20000) + strings.Repeat("]", 20000) + "}")
+	res = v.ParseFormat(deepJSON, u.FormatJSON)
+	assert.False(res.IsValid())
+
+	// a registered decoder which doesn't apply the limit itself is checked afterwards
+	u.RegisterDecoder("application/x-nested", func(data []byte, maxDepth int) (map[string]interface{}, error) {
+		return map[string]interface{}{"a": []any{[]any{map[string]interface{}{"b": 1}}}}, nil
+	})
+	res = u.Object(u.MaxJSONDepth(3)).ParseFormat([]byte("{}"), "application/x-nested")
+	assert.False(res.IsValid())
+	assert.ErrorIs(res.Errors()[0], u.DocumentTooDeepError)
+	assert.True(u.Object(u.MaxJSONDepth(4)).ParseFormat([]byte("{}"), "application/x-nested").IsValid())
+
+	res = u.Object(u.MaxJSONDepth(2)).ParseFormat([]byte(`<item><a><b><c>1</c></b></a></item>`), u.FormatXML)
+	assert.False(res.IsValid())
+	assert.ErrorIs(res.Errors()[0], u.DocumentTooDeepError)
+}
+
+func TestParseFormatBodySize(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Object(u.WithMaxBodySize(16)).String("Name")
+	res := v.ParseFormat([]byte(`<item><Name>abcdefgh</Name></item>`), u.FormatXML)
+	assert.False(res.IsValid())
+	assert.Equal("request body too large", res.Errors()[0].Error())
+}
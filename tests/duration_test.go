@@ -0,0 +1,143 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"testing"
+	"time"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Duration(u.MinDuration(time.Minute), u.MaxDuration(30*24*time.Hour))
+
+	for s, expected := range map[string]time.Duration{
+		"1h30m":     90 * time.Minute,
+		"P1DT2H":    26 * time.Hour,
+		"PT15M":     15 * time.Minute,
+		"P2W":       14 * 24 * time.Hour,
+		"PT1.5H":    90 * time.Minute,
+		"PT0,5M30S": time.Minute,
+	} {
+		res := v.Parse(s)
+		assert.True(res.IsValid(), s)
+		assert.Equal(expected, res.Get(), s)
+	}
+
+	assert.True(v.Parse(5 * time.Minute).IsValid())
+
+	errs := v.Parse("30s").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("duration_too_short", errs[0].Code())
+
+	errs = v.Parse("P31D").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("duration_too_long", errs[0].Code())
+	assert.Equal("720h0m0s", errs[0].Params()["max"])
+
+	errs = v.Parse("P1M").Errors()
+	assert.Equal(1, len(errs))
+	assert.ErrorIs(errs[0], u.AmbiguousDurationError)
+
+	for _, s := range []string{"P", "PT", "1 hour", "P1H", "T1H"} {
+		errs = v.Parse(s).Errors()
+		assert.Equal(1, len(errs), s)
+		assert.ErrorIs(errs[0], u.InvalidDurationError, s)
+	}
+
+	errs = v.Parse(90).Errors()
+	assert.Equal(1, len(errs))
+	assert.ErrorIs(errs[0], u.InvalidTypeError)
+}
+
+func TestInterval(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Interval(u.MaxSpan(90 * 24 * time.Hour))
+
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	res := v.Parse("2024-01-01/2024-02-01")
+	assert.True(res.IsValid())
+	assert.Equal(u.TimeInterval{Start: jan, End: feb}, res.Get())
+
+	res = v.Parse(map[string]any{"start": "2024-01-01", "end": "2024-02-01"})
+	assert.True(res.IsValid())
+	assert.Equal(31*24*time.Hour, res.Get().Duration())
+	assert.True(res.Get().Contains(jan))
+	assert.False(res.Get().Contains(feb))
+
+	res = v.Parse("2024-01-01T09:00:00Z/PT8H")
+	assert.True(res.IsValid())
+	assert.Equal(17, res.Get().End.Hour())
+
+	res = v.Parse("P1D/2024-02-01")
+	assert.True(res.IsValid())
+	assert.Equal(31, res.Get().Start.Day())
+
+	errs := v.Parse("2024-02-01/2024-01-01").Errors()
+	assert.Equal(1, len(errs))
+	assert.ErrorIs(errs[0], u.IntervalOrderError)
+
+	errs = v.Parse(u.TimeInterval{Start: feb, End: jan}).Errors()
+	assert.Equal(1, len(errs))
+	assert.ErrorIs(errs[0], u.IntervalOrderError)
+
+	errs = v.Parse("2024-01-01/2024-06-01").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("interval_too_long", errs[0].Code())
+
+	for _, val := range []any{"2024-01-01", "P1D/PT1H", "2024-01-01/soon", map[string]any{"start": "2024-01-01"}} {
+		assert.False(v.Parse(val).IsValid(), val)
+	}
+
+	london, _ := time.LoadLocation("Europe/London")
+	v = u.Interval(u.AssumeLocation(london), u.InLocation(time.UTC))
+	res = v.Parse("2024-07-01T09:00/2024-07-01T17:00")
+	assert.Equal(8, res.Get().Start.Hour())
+	assert.Equal(time.UTC, res.Get().Start.Location())
+}
+
+func TestDurationObject(t *testing.T) {
+	assert := assert.New(t)
+
+	type sla struct {
+		Response time.Duration  `json:"response"`
+		Period   u.TimeInterval `json:"period"`
+	}
+
+	v := u.Object().
+		Duration("response", u.Required(), u.MaxDuration(4*time.Hour)).
+		Interval("period", u.Required())
+
+	res := v.Parse([]byte(`{"response":"PT2H","period":{"start":"2024-01-01","end":"2024-04-01"}}`))
+	assert.True(res.IsValid())
+
+	s := sla{}
+	assert.NoError(res.Unmarshal(&s))
+	assert.Equal(2*time.Hour, s.Response)
+	assert.Equal(time.April, s.Period.End.Month())
+
+	res = v.Parse([]byte(`{"response":"PT5H","period":"2024-04-01/2024-01-01"}`))
+	assert.False(res.IsValid())
+	assert.Equal(2, len(res.Errors()))
+}
@@ -0,0 +1,127 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubMXResolver map[string][]*net.MX
+
+func (r stubMXResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	records, ok := r[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+func TestEmail(t *testing.T) {
+	assert := assert.New(t)
+
+	loose := u.String(u.Email())
+	assert.True(loose.Parse("John Doe <john@example.com>").IsValid())
+	assert.True(loose.Parse("john@localhost").IsValid())
+
+	bare := u.String(u.Email(u.BareAddress(), "Please enter a valid email address"))
+	assert.True(bare.Parse("john@example.com").IsValid())
+	assert.True(bare.Parse("josé@exämple.de").IsValid())
+	for _, val := range []string{
+		"John Doe <john@example.com>",
+		"<john@example.com>",
+		"john@localhost",
+		"john@-example.com",
+		"john",
+		strings.Repeat("a", 65) + "@example.com",
+		"john@" + strings.Repeat("a", 63) + "." + strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) + "." + strings.Repeat("d", 60) + ".com",
+	} {
+		res := bare.Parse(val)
+		if assert.False(res.IsValid(), val) {
+			assert.Equal("Please enter a valid email address", res.Errors()[0].Error())
+			assert.Equal("invalid_email", res.Errors()[0].Code())
+		}
+	}
+}
+
+func TestEmailNormalize(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.String(u.Email(u.BareAddress(), u.NormalizeEmail()))
+	assert.Equal("John.Smith@example.com", v.Parse("John.Smith@Example.COM").Get())
+	assert.Equal("info@xn--mnchen-3ya.de", v.Parse("info@München.de").Get())
+
+	v = u.String(u.Email(u.BareAddress(), u.CanonicalGmail()))
+	assert.Equal("johnsmith@gmail.com", v.Parse("John.Smith+news@googlemail.com").Get())
+	assert.Equal("john.smith+news@example.com", v.Parse("john.smith+news@example.com").Get())
+}
+
+func TestEmailDomainChecks(t *testing.T) {
+	assert := assert.New(t)
+
+	resolver := stubMXResolver{
+		"example.com":        {{Host: "mx.example.com.", Pref: 10}},
+		"mailinator.com":     {{Host: "mx.mailinator.com.", Pref: 10}},
+		"nomail.example.com": {{Host: ".", Pref: 0}},
+	}
+	v := u.Object().String("email", u.Email(
+		u.BareAddress(),
+		u.WithDomainChecker(u.NewDomainBlocklist("mailinator.com")),
+		u.WithDomainChecker(u.NewMXChecker(resolver))))
+
+	res := v.Parse(map[string]any{"email": "john@Example.com"})
+	assert.True(res.IsValid())
+
+	for _, val := range []string{"john@mailinator.com", "john@eu.mailinator.com", "john@nomail.example.com", "john@unknown.example"} {
+		res = v.Parse(map[string]any{"email": val})
+		if assert.False(res.IsValid(), val) {
+			assert.Equal("email domain not accepted", res.GetError("email"))
+			assert.Equal("email_domain_rejected", res.Errors()[0].Code())
+		}
+	}
+
+	var dnsErr *net.DNSError
+	res = v.Parse(map[string]any{"email": "john@unknown.example"})
+	assert.True(errors.As(res.Errors()[0].Inner()[0], &dnsErr))
+
+	blocking := u.WithDomainChecker(u.EmailDomainCheckerFunc(func(ctx context.Context, domain string) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	slow := u.String(u.Email(u.WithDomainCheckTimeout(time.Millisecond), blocking))
+	assert.False(slow.Parse("john@example.com").IsValid())
+
+	// the checks are made with the caller's context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errs := u.String(u.Email(u.WithDomainCheckContext(ctx), blocking)).Parse("john@example.com").Errors()
+	assert.Equal(1, len(errs))
+	assert.ErrorIs(errs[0].Inner()[0], context.Canceled)
+
+	// the message replaces the message of a rejected domain as well as an invalid address
+	v = u.Object().String("email", u.Email("use a work address", u.WithDomainChecker(u.NewDomainBlocklist("mailinator.com"))))
+	res = v.Parse(map[string]any{"email": "john@mailinator.com"})
+	assert.Equal("use a work address", res.GetError("email"))
+	assert.Equal("email_domain_rejected", res.Errors()[0].Code())
+}
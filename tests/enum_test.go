@@ -0,0 +1,126 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+type Plan string
+
+const (
+	PlanPersonal Plan = "personal"
+	PlanStarter  Plan = "starter"
+	PlanPro      Plan = "pro"
+)
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityMedium
+	PriorityHigh
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "Low"
+	case PriorityMedium:
+		return "Medium"
+	case PriorityHigh:
+		return "High"
+	}
+	return "Priority(?)"
+}
+
+type Colour string
+
+func (c *Colour) UnmarshalText(text []byte) error {
+	switch s := strings.ToLower(string(text)); s {
+	case "red", "green", "blue":
+		*c = Colour(s)
+		return nil
+	}
+	return errors.New("unknown colour")
+}
+
+func TestEnumOf(t *testing.T) {
+	assert := assert.New(t)
+
+	plans := u.String(u.EnumOf(PlanPersonal, PlanStarter, PlanPro))
+	assert.Equal("pro", plans.Parse("pro").Get())
+	assert.Equal("pro", plans.Parse(PlanPro).Get())
+
+	res := plans.Parse("PRO")
+	assert.False(res.IsValid())
+	assert.Equal("value not found in enum", res.Errors()[0].Error())
+	assert.Equal("invalid_enum", res.Errors()[0].Code())
+	assert.Equal(map[string]any{"value": "PRO", "allowed": []string{"personal", "starter", "pro"}}, res.Errors()[0].Params())
+
+	folded := u.String(u.EnumOf(PlanPersonal, PlanStarter, PlanPro).CaseInsensitive().Alias("premium", PlanPro))
+	assert.Equal("pro", folded.Parse("PRO").Get())
+	assert.Equal("pro", folded.Parse("Premium").Get())
+
+	priorities := u.Int(u.EnumRange(PriorityLow, PriorityHigh).CaseInsensitive())
+	assert.Equal(2, priorities.Parse("High").Get())
+	assert.Equal(1, priorities.Parse("medium").Get())
+	assert.Equal(0, priorities.Parse(0).Get())
+	assert.Equal(2, priorities.Parse("2").Get())
+	errs := priorities.Parse(7).Errors()
+	assert.Equal([]string{"Low", "Medium", "High"}, errs[0].Params()["allowed"])
+
+	colours := u.String(u.EnumOf[Colour]())
+	assert.Equal("red", colours.Parse("Red").Get())
+	assert.False(colours.Parse("purple").IsValid())
+
+	assert.Error(u.String(u.EnumOf[Plan]()).Error())
+
+	errs = u.String(u.Enum("a", "b")).Parse("c").Errors()
+	assert.Equal(map[string]any{"value": "c", "allowed": []string{"a", "b"}}, errs[0].Params())
+}
+
+func TestEnumUnmarshal(t *testing.T) {
+	assert := assert.New(t)
+
+	type Ticket struct {
+		Plan     Plan     `json:"plan"`
+		Priority Priority `json:"priority"`
+	}
+
+	v := u.Object().
+		String("plan", u.EnumOf(PlanPersonal, PlanStarter, PlanPro)).
+		Int("priority", u.EnumRange(PriorityLow, PriorityHigh))
+
+	res := v.Parse([]byte(`{"plan": "starter", "priority": "High"}`))
+	assert.True(res.IsValid())
+
+	ticket := &Ticket{}
+	assert.NoError(res.Unmarshal(ticket))
+	assert.Equal(PlanStarter, ticket.Plan)
+	assert.Equal(PriorityHigh, ticket.Priority)
+
+	res = v.Parse(map[string]any{"plan": PlanPro, "priority": PriorityMedium})
+	assert.True(res.IsValid())
+	assert.Equal("pro", res.GetString("plan"))
+	assert.Equal(1, res.GetInt("priority"))
+}
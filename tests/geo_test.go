@@ -0,0 +1,234 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"encoding/json"
+	"testing"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatLng(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.LatLng()
+	london := u.GeoPoint{Lat: 51.5, Lng: -0.12}
+	for _, val := range []any{
+		map[string]any{"lat": 51.5, "lng": -0.12},
+		map[string]any{"lat": "51.5", "lng": json.Number("-0.12")},
+		[]any{-0.12, 51.5},
+		[]float64{-0.12, 51.5, 11},
+		" 51.5, -0.12 ",
+	} {
+		res := v.Parse(val)
+		if assert.True(res.IsValid(), val) {
+			assert.Equal(london, res.Get(), val)
+		}
+	}
+
+	for _, val := range []any{
+		map[string]any{"lat": 91.0, "lng": 0.0},
+		map[string]any{"lat": 51.5},
+		[]any{51.5, -181.0},
+		[]float64{-0.12},
+		"51.5",
+		"north,west",
+		"NaN,0",
+	} {
+		errs := v.Parse(val).Errors()
+		if assert.Equal(1, len(errs), val) {
+			assert.Equal("invalid_coordinates", errs[0].Code(), val)
+		}
+	}
+
+	// the bounding box crosses the antimeridian
+	v = u.LatLng(u.WithinBoundingBox(170, -50, -170, -30))
+	assert.True(v.Parse("-41.3,174.8").IsValid())
+	assert.True(v.Parse("-44.0,-176.5").IsValid())
+	errs := v.Parse("51.5,-0.12").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("outside_bounding_box", errs[0].Code())
+	assert.Equal([]float64{170, -50, -170, -30}, errs[0].Params()["bbox"])
+
+	res := u.Object().LatLng("location", u.Required()).Parse(map[string]any{"location": "51.5,-0.12"})
+	assert.True(res.IsValid())
+	assert.Equal(london, res.GetField("location").Get())
+}
+
+func TestGeoJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.GeoJSON()
+	res := v.Parse(`{"type": "Point", "coordinates": [-0.12, 51.5]}`)
+	if assert.True(res.IsValid()) {
+		assert.Equal(u.Geometry{Type: u.GeometryPoint, Point: u.GeoPoint{Lat: 51.5, Lng: -0.12}}, res.Get())
+	}
+
+	res = v.Parse(map[string]any{"type": "LineString", "coordinates": [][]float64{{0, 0}, {1, 1}}})
+	assert.True(res.IsValid())
+	assert.Equal(2, res.Get().Vertices())
+
+	square := `{"type": "Polygon", "coordinates": [[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]], [[2, 2], [2, 4], [4, 4], [2, 2]]]}`
+	res = v.Parse(square)
+	if assert.True(res.IsValid()) {
+		assert.Equal(9, res.Get().Vertices())
+		data, err := json.Marshal(res.Get())
+		assert.NoError(err)
+		assert.JSONEq(square, string(data))
+	}
+
+	multi := map[string]any{"type": "MultiPolygon", "coordinates": []any{
+		[][][]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}},
+		[][][]float64{{{5, 5}, {6, 5}, {6, 6}, {5, 5}}},
+	}}
+	res = v.Parse(multi)
+	assert.True(res.IsValid())
+	assert.Equal(2, len(res.Get().MultiPolygon))
+
+	testCases := []struct {
+		val  any
+		code string
+	}{
+		{`{"type": "Polygon", "coordinates": [[[0, 0], [10, 0], [10, 10], [0, 10]]]}`, "ring_not_closed"},
+		{`{"type": "Polygon", "coordinates": [[[0, 0], [10, 0], [0, 0]]]}`, "too_few_positions"},
+		{`{"type": "Polygon", "coordinates": []}`, "too_few_positions"},
+		{`{"type": "LineString", "coordinates": [[0, 0]]}`, "too_few_positions"},
+		{`{"type": "Polygon", "coordinates": [[[0, 0], [200, 0], [10, 10], [0, 0]]]}`, "invalid_coordinates"},
+		{`{"type": "Point", "coordinates": [0]}`, "invalid_coordinates"},
+		{`{"type": "Point", "coordinates": "0,0"}`, "invalid_coordinates"},
+		{`{"type": "Polygon", "coordinates": [0, 0]}`, "invalid_geometry"},
+		{`{"type": "GeometryCollection", "geometries": []}`, "invalid_geometry_type"},
+		{`{"coordinates": [0, 0]}`, "invalid_geometry"},
+		{`{"type": "Point", `, "invalid_geometry"},
+		{u.Geometry{Type: u.GeometryLineString}, "too_few_positions"},
+	}
+	for _, tc := range testCases {
+		errs := v.Parse(tc.val).Errors()
+		if assert.Equal(1, len(errs), tc.val) {
+			assert.Equal(tc.code, errs[0].Code(), tc.val)
+		}
+	}
+}
+
+func TestGeoJSONRings(t *testing.T) {
+	assert := assert.New(t)
+
+	// the exterior ring is clockwise and isn't closed
+	open := `{"type": "Polygon", "coordinates": [[[0, 0], [0, 10], [10, 10], [10, 0]]]}`
+	assert.False(u.GeoJSON().Parse(open).IsValid())
+
+	res := u.GeoJSON(u.CloseRings()).Parse(open)
+	if assert.True(res.IsValid()) {
+		assert.Equal(5, len(res.Get().Polygon[0]))
+		assert.Equal(res.Get().Polygon[0][0], res.Get().Polygon[0][4])
+	}
+
+	errs := u.GeoJSON(u.CloseRings(), u.RequireRightHandRule()).Parse(open).Errors()
+	if assert.Equal(1, len(errs)) {
+		assert.Equal("invalid_winding", errs[0].Code())
+		assert.Equal(0, errs[0].Params()["ring"])
+	}
+
+	res = u.GeoJSON(u.CloseRings(), u.RewindRings(), u.RequireRightHandRule()).Parse(open)
+	if assert.True(res.IsValid()) {
+		assert.Equal(u.GeoPoint{Lat: 0, Lng: 10}, res.Get().Polygon[0][1])
+	}
+
+	// the caller's geometry isn't changed
+	g := u.Geometry{Type: u.GeometryPolygon, Polygon: [][]u.GeoPoint{{{Lat: 0, Lng: 0}, {Lat: 1, Lng: 0}, {Lat: 0, Lng: 1}}}}
+	res = u.GeoJSON(u.CloseRings()).Parse(g)
+	assert.True(res.IsValid())
+	assert.Equal(3, len(g.Polygon[0]))
+	assert.Equal(4, len(res.Get().Polygon[0]))
+}
+
+func TestGeoJSONOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	zone := `{"type": "Polygon", "coordinates": [[[-0.2, 51.4], [0, 51.4], [0, 51.6], [-0.2, 51.6], [-0.2, 51.4]]]}`
+	v := u.GeoJSON(u.GeometryTypes(u.GeometryPolygon, u.GeometryMultiPolygon), u.MaxVertices(5),
+		u.WithinBoundingBox(-8.7, 49.8, 1.8, 60.9, "delivery zones must be in the UK"))
+	assert.True(v.Parse(zone).IsValid())
+
+	errs := v.Parse(`{"type": "Point", "coordinates": [-0.12, 51.5]}`).Errors()
+	if assert.Equal(1, len(errs)) {
+		assert.Equal("geometry_type_not_allowed", errs[0].Code())
+	}
+
+	errs = v.Parse(`{"type": "Polygon", "coordinates": [[[-0.2, 51.4], [0, 51.4], [0, 51.5], [0, 51.6], [-0.2, 51.6], [-0.2, 51.4]]]}`).Errors()
+	if assert.Equal(1, len(errs)) {
+		assert.Equal("too_many_vertices", errs[0].Code())
+		assert.Equal(5, errs[0].Params()["max"])
+	}
+
+	errs = v.Parse(`{"type": "Polygon", "coordinates": [[[2.3, 48.8], [2.4, 48.8], [2.4, 48.9], [2.3, 48.8]]]}`).Errors()
+	if assert.Equal(1, len(errs)) {
+		assert.Equal("outside_bounding_box", errs[0].Code())
+		assert.Equal("delivery zones must be in the UK", errs[0].Error())
+	}
+
+	// vertices are counted before the positions are read
+	huge := make([]any, 10000)
+	for i := range huge {
+		huge[i] = []any{"not", "a number"}
+	}
+	errs = v.Parse(map[string]any{"type": "Polygon", "coordinates": []any{huge}}).Errors()
+	if assert.Equal(1, len(errs)) {
+		assert.Equal("too_many_vertices", errs[0].Code())
+	}
+	errs = u.GeoJSON(u.MaxVertices(3)).Parse(u.Geometry{Type: u.GeometryLineString, LineString: make([]u.GeoPoint, 4)}).Errors()
+	if assert.Equal(1, len(errs)) {
+		assert.Equal("too_many_vertices", errs[0].Code())
+	}
+
+	res := u.Object().
+		String("name", u.Required()).
+		GeoJSON("area", u.Required(), u.CloseRings(), u.RewindRings()).
+		Parse(map[string]any{"name": "central", "area": json.RawMessage(zone)})
+	assert.True(res.IsValid())
+	assert.Equal(u.GeometryPolygon, res.GetField("area").Get().(u.Geometry).Type)
+}
+
+func TestGeometryJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, data := range []string{
+		`{"type": "Point", "coordinates": [-0.12, 51.5]}`,
+		`{"type": "LineString", "coordinates": [[0, 0], [1, 1]]}`,
+		`{"type": "Polygon", "coordinates": [[[0, 0], [10, 0], [10, 10], [0, 10], [0, 0]], [[2, 2], [2, 4], [4, 4], [2, 2]]]}`,
+		`{"type": "MultiPolygon", "coordinates": [[[[0, 0], [1, 0], [1, 1], [0, 0]]], [[[5, 5], [6, 5], [6, 6], [5, 5]]]]}`,
+	} {
+		var g u.Geometry
+		if assert.NoError(json.Unmarshal([]byte(data), &g), data) {
+			out, err := json.Marshal(g)
+			assert.NoError(err)
+			assert.JSONEq(data, string(out))
+
+			var again u.Geometry
+			assert.NoError(json.Unmarshal(out, &again))
+			assert.Equal(g, again)
+		}
+	}
+
+	var g u.Geometry
+	assert.Error(json.Unmarshal([]byte(`{"type": "Polygon", "coordinates": [[[0, 0], [10, 0], [10, 10], [0, 10]]]}`), &g))
+	assert.Error(json.Unmarshal([]byte(`{"type": "Point", "coordinates": [0, 100]}`), &g))
+	assert.Error(json.Unmarshal([]byte(`{"type": "Circle"}`), &g))
+	assert.Equal(u.Geometry{}, g)
+}
@@ -0,0 +1,109 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Object(
+		u.MaxJSONDepth(3),
+		u.MaxArrayLength(3),
+		u.MaxObjectKeys(4),
+		u.RejectDuplicateKeys(),
+	).
+		String("Name").
+		Int("Count")
+
+	testCases := []struct {
+		name string
+		data string
+		err  error
+		path string
+	}{
+		{"valid", `{ "Name": "abcdef", "Count": 5, "Tags": ["a", "b"], "Meta": { "a": [1] } }`, nil, ""},
+		{"too deep", `{ "Name": "abcdef", "Meta": { "a": { "b": [1] } } }`, u.JSONTooDeepError, "Meta.a.b"},
+		{"array too long", `{ "Name": "abcdef", "Tags": ["a", "b", "c", "d"] }`, u.JSONArrayTooLongError, "Tags"},
+		{"too many keys", `{ "a": 1, "b": 2, "c": 3, "d": 4, "e": 5 }`, u.JSONTooManyKeysError, ""},
+		{"duplicate key", `{ "Name": "abcdef", "Name": "ghijkl" }`, u.JSONDuplicateKeyError, "Name"},
+		{"trailing data", `{ "Name": "abcdef" } { "Name": "ghijkl" }`, u.JSONTrailingDataError, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := v.Parse([]byte(tc.data))
+			if tc.err == nil {
+				assert.True(res.IsValid())
+				assert.Equal(0, len(res.Errors()))
+				return
+			}
+			assert.False(res.IsValid())
+			assert.Equal(1, len(res.Errors()))
+			assert.EqualError(res.Errors()[0], tc.err.Error())
+			assert.Equal(tc.path, res.Errors()[0].Path())
+			assert.ErrorIs(res.Errors()[0], tc.err)
+		})
+	}
+}
+
+func TestJSONTrailingDataStream(t *testing.T) {
+	assert := assert.New(t)
+
+	data := `[{"Name": "abc"}] {"Name": "def"}`
+
+	count := 0
+	for range u.Object().String("Name").ParseStream(strings.NewReader(data)) {
+		count++
+	}
+	assert.Equal(1, count)
+
+	errs := make([]string, 0)
+	for _, res := range u.Object(u.DisallowTrailingData()).String("Name").ParseStream(strings.NewReader(data)) {
+		for _, err := range res.Errors() {
+			errs = append(errs, err.Error())
+		}
+	}
+	assert.Equal([]string{u.JSONTrailingDataError.Error()}, errs)
+}
+
+func TestJSONArrayLimitStream(t *testing.T) {
+	assert := assert.New(t)
+
+	data := `[{"Name": "a"}, {"Name": "b"}, {"Name": "c"}]`
+
+	lines := make([]int, 0)
+	errs := make([]error, 0)
+	for line, res := range u.Object(u.MaxArrayLength(2)).String("Name").ParseStream(strings.NewReader(data)) {
+		lines = append(lines, line)
+		for _, err := range res.Errors() {
+			errs = append(errs, err)
+		}
+	}
+	assert.Equal([]int{1, 1, 1}, lines)
+	if assert.Equal(1, len(errs)) {
+		assert.True(errors.Is(errs[0], u.JSONArrayTooLongError))
+		assert.False(errors.Is(errs[0], u.JSONTooDeepError))
+	}
+}
@@ -0,0 +1,83 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"testing"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoney(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Money(
+		u.AllowedCurrencies("EUR", "jpy", "Bhd"),
+		u.MinMoney("EUR 1.00"),
+		u.MaxMoney("EUR 1000.00"),
+		u.Max(1000000))
+
+	testCases := []struct {
+		name  string
+		input any
+		want  u.MonetaryAmount
+		err   string
+	}{
+		{"object", map[string]any{"amount": "12.34", "currency": "EUR"}, u.MonetaryAmount{MinorUnits: 1234, Currency: "EUR"}, ""},
+		{"string", "EUR 12.3", u.MonetaryAmount{MinorUnits: 1230, Currency: "EUR"}, ""},
+		{"amount first", "500 jpy", u.MonetaryAmount{MinorUnits: 500, Currency: "JPY"}, ""},
+		{"three places", "BHD 1.234", u.MonetaryAmount{MinorUnits: 1234, Currency: "BHD"}, ""},
+		{"too precise", "JPY 1.5", u.MonetaryAmount{}, u.MoneyPrecisionError.Error()},
+		{"unknown currency", "XYZ 1.00", u.MonetaryAmount{}, u.InvalidCurrencyError.Error()},
+		{"not allowed", "USD 1.00", u.MonetaryAmount{}, "currency not allowed"},
+		{"too small", "EUR 0.99", u.MonetaryAmount{}, "amount too small"},
+		{"too large", "EUR 1000.01", u.MonetaryAmount{}, "amount too large"},
+		{"numeric max", "JPY 1000001", u.MonetaryAmount{}, "number too large"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := v.Parse(tc.input)
+			if tc.err > "" {
+				assert.False(res.IsValid())
+				assert.Equal(tc.err, res.Errors()[0].Error())
+				return
+			}
+			assert.True(res.IsValid())
+			assert.Equal(tc.want, res.Get())
+		})
+	}
+}
+
+func TestMoneyObject(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Object().
+		Money("price", u.Required())
+
+	res := v.Parse([]byte(`{ "price": { "amount": 12.34, "currency": "EUR" } }`))
+	assert.True(res.IsValid())
+	assert.Equal("EUR 12.34", res.GetString("price"))
+
+	tgt := struct {
+		Price u.MonetaryAmount `json:"price"`
+	}{}
+	assert.NoError(res.Unmarshal(&tgt))
+	assert.Equal(int64(1234), tgt.Price.MinorUnits)
+	assert.Equal("12.34", tgt.Price.Decimal().FloatString(2))
+}
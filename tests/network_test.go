@@ -0,0 +1,283 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubHostResolver map[string][]netip.Addr
+
+func (r stubHostResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	addrs, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return addrs, nil
+}
+
+// publicHosts resolves the names used by the tests to public addresses
+var publicHosts = stubHostResolver{
+	"hooks.example.com": {netip.MustParseAddr("93.184.216.34")},
+	"cache.example.com": {netip.MustParseAddr("93.184.216.35")},
+}
+
+func TestIPAddr(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.IPAddr()
+	res := v.Parse(" 192.0.2.1 ")
+	assert.True(res.IsValid())
+	assert.Equal(netip.MustParseAddr("192.0.2.1"), res.Get())
+
+	res = v.Parse(net.ParseIP("2001:db8::1"))
+	assert.Equal(netip.MustParseAddr("2001:db8::1"), res.Get())
+	res = v.Parse(net.ParseIP("192.0.2.1"))
+	assert.Equal(netip.MustParseAddr("192.0.2.1"), res.Get())
+
+	errs := v.Parse("192.0.2.256").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("invalid_ip", errs[0].Code())
+
+	v = u.IPAddr(u.PrivateIPAllowed(false))
+	assert.True(v.Parse("8.8.8.8").IsValid())
+	errs = v.Parse("192.168.1.10").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("private_ip", errs[0].Code())
+	assert.False(v.Parse("fd00::1").IsValid())
+
+	v = u.IPAddr(u.InPrefix("10.0.0.0/8", "2001:db8::/32"))
+	assert.True(v.Parse("10.1.2.3").IsValid())
+	assert.True(v.Parse("2001:db8::5").IsValid())
+	errs = v.Parse("11.0.0.1").Errors()
+	assert.Equal("ip_not_in_prefix", errs[0].Code())
+
+	assert.False(u.IPAddr(u.InPrefix("10.0.0.0/33")).Parse("10.0.0.1").IsValid())
+}
+
+func TestIPAddrPublic(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.IPAddr(u.PublicAddressOnly())
+	for _, addr := range []string{"1.1.1.1", "2606:4700:4700::1111"} {
+		assert.True(v.Parse(addr).IsValid(), addr)
+	}
+	for _, addr := range []string{
+		"127.0.0.1", "::1", "10.0.0.1", "169.254.169.254", "fd00:ec2::254", "100.100.100.200", "0.0.0.0",
+		"::ffff:127.0.0.1", "fe80::1%eth0", "224.0.0.1", "64:ff9b::7f00:1", "::127.0.0.1", "2002:7f00:1::1",
+		"2001:0:4136:e378:8000:63bf:3fff:fdd2",
+	} {
+		errs := v.Parse(addr).Errors()
+		assert.Equal(1, len(errs), addr)
+		assert.Equal("internal_address", errs[0].Code(), addr)
+	}
+}
+
+func TestIPPrefix(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.IPPrefix(u.InPrefix("10.0.0.0/8"))
+	res := v.Parse("10.1.0.0/16")
+	assert.True(res.IsValid())
+	assert.Equal(netip.MustParsePrefix("10.1.0.0/16"), res.Get())
+
+	// a larger prefix isn't inside the range even though its address is
+	assert.False(v.Parse("10.0.0.0/7").IsValid())
+
+	_, ipNet, _ := net.ParseCIDR("10.2.0.0/16")
+	assert.True(v.Parse(ipNet).IsValid())
+
+	errs := v.Parse("10.0.0.0").Errors()
+	assert.Equal("invalid_cidr", errs[0].Code())
+
+	assert.False(u.IPPrefix(u.PublicAddressOnly()).Parse("0.0.0.0/0").IsValid())
+
+	// a prefix which contains a private range is rejected even though its base address is public
+	v = u.IPPrefix(u.PrivateIPAllowed(false))
+	assert.True(v.Parse("8.8.8.0/24").IsValid())
+	for _, val := range []string{"0.0.0.0/0", "8.0.0.0/6", "172.0.0.0/8", "::/0", "10.1.0.0/16"} {
+		errs := v.Parse(val).Errors()
+		if assert.Equal(1, len(errs), val) {
+			assert.Equal("private_ip", errs[0].Code(), val)
+		}
+	}
+}
+
+func TestParsedURL(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.ParsedURL(u.Scheme("https"), u.PublicAddressOnly(), u.PortRange(443, 443), u.WithHostResolver(publicHosts))
+
+	res := v.Parse("https://hooks.example.com/events?id=1")
+	assert.True(res.IsValid())
+	assert.Equal("hooks.example.com", res.Get().Host)
+	assert.Equal("/events", res.Get().Path)
+
+	parsed, _ := url.Parse("https://hooks.example.com/events")
+	res = v.Parse(parsed)
+	assert.True(res.IsValid())
+	assert.NotSame(parsed, res.Get())
+
+	for val, code := range map[string]string{
+		"ftp://hooks.example.com:443":      "invalid_scheme",
+		"https://hooks.example.com:8443":   "port_out_of_range",
+		"https://127.0.0.1/":               "internal_address",
+		"https://[::ffff:169.254.169.254]": "internal_address",
+		"https://localhost/":               "internal_address",
+		"https://api.localhost/":           "internal_address",
+		"https://metadata.google.internal": "internal_address",
+		"https://2130706433/":              "invalid_url",
+		"https://0x7f.1/":                  "invalid_url",
+		"https://0x7f000001/":              "invalid_url",
+		"https://0177.0.0.1/":              "invalid_url",
+		"https://127.0.0.0x1/":             "invalid_url",
+		"/relative/path":                   "invalid_url",
+		"https://hooks.example.com:99999":  "invalid_url",
+	} {
+		errs := v.Parse(val).Errors()
+		if assert.Equal(1, len(errs), val) {
+			assert.Equal(code, errs[0].Code(), val)
+		}
+	}
+
+	// a port can't be checked when the scheme has no well known port
+	v = u.ParsedURL(u.PortRange(443, 443))
+	assert.True(v.Parse("https://a.com/").IsValid())
+	assert.True(v.Parse("redis://a.com:443/").IsValid())
+	for _, val := range []string{"gopher://a.com/", "redis://a.com/"} {
+		errs := v.Parse(val).Errors()
+		if assert.Equal(1, len(errs), val) {
+			assert.Equal("port_unknown", errs[0].Code(), val)
+		}
+	}
+	assert.False(u.ParsedURL(u.PublicAddressOnly()).Parse("http://[::127.0.0.1]/").IsValid())
+
+	// names can't be checked without a resolver so they are rejected
+	errs := u.ParsedURL(u.PublicAddressOnly()).Parse("http://127.0.0.1.nip.io/").Errors()
+	if assert.Equal(1, len(errs)) {
+		assert.Equal("resolver_required", errs[0].Code())
+	}
+
+	v = u.ParsedURL(u.HostAllowlist("example.com", "*.example.net"))
+	assert.True(v.Parse("https://example.com/").IsValid())
+	assert.True(v.Parse("https://api.EXAMPLE.net/").IsValid())
+	assert.False(v.Parse("https://example.net/").IsValid())
+	errs = v.Parse("https://evil.com/").Errors()
+	assert.Equal("host_not_allowed", errs[0].Code())
+	assert.Equal("evil.com", errs[0].Params()["host"])
+}
+
+func TestParsedURLResolver(t *testing.T) {
+	assert := assert.New(t)
+
+	resolver := stubHostResolver{
+		"hooks.example.com":  {netip.MustParseAddr("93.184.216.34")},
+		"rebind.example.com": {netip.MustParseAddr("93.184.216.34"), netip.MustParseAddr("10.0.0.5")},
+	}
+	v := u.ParsedURL(u.PublicAddressOnly(), u.WithHostResolver(resolver))
+
+	assert.True(v.Parse("https://hooks.example.com/").IsValid())
+
+	errs := v.Parse("https://rebind.example.com/").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("internal_address", errs[0].Code())
+
+	errs = v.Parse("https://missing.example.com/").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("host_unresolved", errs[0].Code())
+
+	// lookups are made with the caller's context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errs = u.ParsedURL(u.PublicAddressOnly(), u.WithHostResolver(resolver), u.WithHostResolveContext(ctx)).Parse("https://hooks.example.com/").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("host_unresolved", errs[0].Code())
+
+	// without IP options nothing is resolved
+	assert.True(u.ParsedURL(u.WithHostResolver(resolver)).Parse("https://missing.example.com/").IsValid())
+}
+
+func TestHostPort(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.HostPort(u.PortRange(1024, 65535))
+	res := v.Parse("db.example.com:5432")
+	assert.True(res.IsValid())
+	assert.Equal(u.HostAndPort{Host: "db.example.com", Port: 5432}, res.Get())
+
+	res = v.Parse("[2001:db8::1]:8080")
+	assert.Equal("2001:db8::1", res.Get().Host)
+	assert.Equal("[2001:db8::1]:8080", res.Get().String())
+
+	errs := v.Parse("db.example.com:22").Errors()
+	assert.Equal("port_out_of_range", errs[0].Code())
+	assert.Equal(1024, errs[0].Params()["min"])
+
+	for _, val := range []string{"db.example.com", "db.example.com:0", "db.example.com:70000", "bad host:80"} {
+		errs := v.Parse(val).Errors()
+		if assert.Equal(1, len(errs), val) {
+			assert.Equal("invalid_host_port", errs[0].Code(), val)
+		}
+	}
+
+	v = u.HostPort(u.DefaultPort(6379), u.PrivateIPAllowed(true), u.PublicAddressOnly("use a public host"), u.WithHostResolver(publicHosts))
+	res = v.Parse("cache.example.com")
+	assert.Equal(u.HostAndPort{Host: "cache.example.com", Port: 6379}, res.Get())
+	errs = v.Parse("127.0.0.1").Errors()
+	assert.Equal("use a public host", errs[0].Error())
+}
+
+func TestNetworkObject(t *testing.T) {
+	assert := assert.New(t)
+
+	type webhook struct {
+		Callback *url.URL      `json:"callback"`
+		SourceIP netip.Addr    `json:"sourceIp"`
+		Network  netip.Prefix  `json:"network"`
+		Relay    u.HostAndPort `json:"relay"`
+	}
+
+	schema := u.Object().
+		ParsedURL("callback", u.Required(), u.Scheme("https"), u.PublicAddressOnly(), u.WithHostResolver(publicHosts)).
+		IPAddr("sourceIp").
+		IPPrefix("network").
+		HostPort("relay", u.DefaultPort(25))
+
+	res := schema.Parse([]byte(`{"callback":"https://hooks.example.com/in","sourceIp":"192.0.2.7","network":"192.0.2.0/24","relay":"smtp.example.com"}`))
+	assert.True(res.IsValid())
+
+	w := webhook{}
+	assert.NoError(res.Unmarshal(&w))
+	assert.Equal("https://hooks.example.com/in", w.Callback.String())
+	assert.True(w.Network.Contains(w.SourceIP))
+	assert.Equal(uint16(25), w.Relay.Port)
+
+	res = schema.Parse([]byte(`{"callback":"https://169.254.169.254/latest/meta-data"}`))
+	assert.False(res.IsValid())
+	assert.Equal("address is not public", res.GetError("callback"))
+}
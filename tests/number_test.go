@@ -17,7 +17,9 @@ package tests
 // along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
 import (
+	"math/big"
 	"testing"
+	"time"
 
 	u "github.com/jdudmesh/ursa"
 	"github.com/stretchr/testify/assert"
@@ -54,3 +56,90 @@ func TestNumber(t *testing.T) {
 	errs = u2.Parse(0).Errors()
 	assert.Equal(errs[0].Error(), "number is zero")
 }
+
+func TestNumberJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Object().
+		Int64("id").
+		Uint64("ref").
+		Int16("small").
+		Float64("ratio").
+		String("code")
+
+	res := v.Parse([]byte(`{ "id": 1234567890123456789, "ref": 18446744073709551615, "small": 1e3, "ratio": 0.1, "code": 42 }`))
+	assert.True(res.IsValid())
+	assert.Equal(int64(1234567890123456789), res.GetField("id").Get())
+	assert.Equal(uint64(18446744073709551615), res.GetField("ref").Get())
+	assert.Equal("18446744073709551615", res.GetString("ref"))
+	assert.Equal(int16(1000), res.GetField("small").Get())
+	assert.Equal(0.1, res.GetField("ratio").Get())
+	assert.Equal("42", res.GetString("code"))
+
+	res = v.Parse([]byte(`{ "id": 1.5, "small": 40000, "ref": -1, "ratio": 1e400 }`))
+	assert.False(res.IsValid())
+	assert.Equal(u.InexactNumberError.Error(), res.GetError("id"))
+	assert.Equal(u.NumberOutOfRangeError.Error(), res.GetError("small"))
+	assert.Equal(u.NumberOutOfRangeError.Error(), res.GetError("ref"))
+	assert.Equal(u.NumberOutOfRangeError.Error(), res.GetError("ratio"))
+
+	res = u.Object().Int64("id").Parse(map[string]any{"id": "1234567890123456789"})
+	assert.Equal(int64(1234567890123456789), res.GetField("id").Get())
+
+	// transformers receive the exact number rather than a float64
+	res = u.Object().
+		Time("at", u.UnixMillis()).
+		Decimal("price").
+		Parse([]byte(`{ "at": 1700000000123, "price": 0.1 }`))
+	assert.True(res.IsValid())
+	assert.Equal(time.UnixMilli(1700000000123).UTC(), res.GetField("at").Get().(time.Time).UTC())
+	assert.Equal(big.NewRat(1, 10), res.GetField("price").Get())
+}
+
+func TestNumberConstraints(t *testing.T) {
+	assert := assert.New(t)
+
+	price := u.Float64(u.MultipleOf("0.05"), u.Positive())
+	assert.True(price.Parse(0.15).IsValid())
+	assert.True(price.Parse(19.95).IsValid())
+	errs := price.Parse(0.17).Errors()
+	assert.Equal("number is not a multiple of 0.05", errs[0].Error())
+	assert.Equal(map[string]any{"step": "0.05"}, errs[0].Params())
+	assert.False(price.Parse(0).IsValid())
+
+	pct := u.Float64(u.GreaterThan(0), u.LessThan(100), u.MaxDecimalPlaces(2))
+	assert.True(pct.Parse(99.99).IsValid())
+	errs = pct.Parse(100).Errors()
+	assert.Equal("number too large", errs[0].Error())
+	assert.Equal(map[string]any{"lessThan": "100"}, errs[0].Params())
+	errs = pct.Parse(12.345).Errors()
+	assert.Equal(map[string]any{"places": 2}, errs[0].Params())
+
+	assert.True(u.Int(u.Negative()).Parse(-1).IsValid())
+	assert.False(u.Int(u.Negative()).Parse(0).IsValid())
+	assert.True(u.Int(u.NonNegative()).Parse(0).IsValid())
+	errs = u.Int(u.NonNegative()).Parse(-1).Errors()
+	assert.Equal(map[string]any{"min": "0"}, errs[0].Params())
+
+	finite := u.Float64(u.Finite())
+	assert.True(finite.Parse(1.5).IsValid())
+	assert.Equal(u.NonFiniteNumberError.Error(), finite.Parse("NaN").Errors()[0].Error())
+	assert.Equal(u.NonFiniteNumberError.Error(), finite.Parse("+Inf").Errors()[0].Error())
+	assert.False(u.Int().Parse("NaN").IsValid())
+
+	// NaN is reported once whether or not Finite is given
+	errs = u.Float64(u.Finite("enter a number"), u.Min(0), u.Max(10)).Parse("NaN").Errors()
+	if assert.Equal(1, len(errs)) {
+		assert.Equal("enter a number", errs[0].Error())
+	}
+	errs = u.Float64(u.Min(0), u.Max(10)).Parse("NaN").Errors()
+	if assert.Equal(1, len(errs)) {
+		assert.Equal(u.NonFiniteNumberError.Error(), errs[0].Error())
+	}
+
+	size := u.Int(u.OneOf(1, 2, 5, 10))
+	assert.True(size.Parse(5).IsValid())
+	errs = size.Parse(3).Errors()
+	assert.Equal("number not found in allowed values", errs[0].Error())
+	assert.Equal(map[string]any{"values": []string{"1", "2", "5", "10"}}, errs[0].Params())
+}
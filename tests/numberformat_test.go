@@ -0,0 +1,128 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumberFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		locale string
+		input  string
+		want   float64
+	}{
+		{"de", "1.234,56", 1234.56},
+		{"de-AT", "€ 1.234,56", 1234.56},
+		{"fr", "1 234,5", 1234.5},
+		{"fr", "1 234,5 €", 1234.5},
+		{"en", "1,000", 1000},
+		{"en", "$5.00", 5},
+		{"en", "-$5.00", -5},
+		{"en", "12%", 0.12},
+		{"en-IN", "₹1,23,456.78", 123456.78},
+		{"hi", "१२३", 123},
+		{"de-CH", "1'234.5", 1234.5},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.locale+" "+tc.input, func(t *testing.T) {
+			res := u.Object(u.WithNumberFormat(tc.locale)).Float64("amount").Parse(map[string]any{"amount": tc.input})
+			assert.True(res.IsValid())
+			assert.Equal(tc.want, res.GetField("amount").Get())
+		})
+	}
+
+	v := u.Object(u.WithNumberFormat("de")).Int("count").Decimal("price").String("code")
+	res := v.Parse(map[string]any{"count": "1.000", "price": "0,10", "code": "1.000"})
+	assert.True(res.IsValid())
+	assert.Equal(1000, res.GetField("count").Get())
+	assert.Equal(0, big.NewRat(1, 10).Cmp(res.GetField("price").Get().(*big.Rat)))
+	assert.Equal("1.000", res.GetString("code"))
+
+	res = v.Parse(map[string]any{"count": "1,000.5", "price": "1.2.3"})
+	assert.False(res.IsFieldValid("count"))
+	assert.False(res.IsFieldValid("price"))
+
+	// percentages and fractions aren't truncated to fit integers
+	res = v.Parse(map[string]any{"count": "12 %"})
+	assert.Equal(u.InexactNumberError.Error(), res.GetError("count"))
+	res = v.Parse(map[string]any{"count": "2,5"})
+	assert.Equal(u.InexactNumberError.Error(), res.GetError("count"))
+
+	res = v.Parse(map[string]any{"count": "12 apples", "price": "1e3"})
+	assert.Equal(u.InvalidNumberFormatError.Error(), res.GetError("count"))
+	assert.True(res.IsFieldValid("price"))
+
+	// nested objects use the parent's format unless they have their own
+	nested := u.Object(u.WithNumberFormat("de")).
+		Object("line", u.Object().
+			Float64("amount").
+			Object("tax", u.Object().Float64("rate")).
+			Object("shipping", u.Object(u.WithNumberFormat("en")).Float64("cost")))
+	res = nested.Parse(map[string]any{"line": map[string]any{
+		"amount":   "1.234,5",
+		"tax":      map[string]any{"rate": "20 %"},
+		"shipping": map[string]any{"cost": "1,234.5"},
+	}})
+	assert.True(res.IsValid())
+	res = nested.Parse(map[string]any{"line": map[string]any{"amount": "1,234.5"}})
+	assert.False(res.IsValid())
+
+	assert.Error(u.Object(u.WithNumberFormat("xx")).Int("count").Parse(map[string]any{}).Errors()[0])
+}
+
+func TestNumberFormatRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Object(u.WithNumberFormat("en"), u.DetectNumberFormat()).Float64("amount")
+
+	newRequest := func(amount string, language string) *http.Request {
+		form := url.Values{"amount": []string{amount}}
+		req, _ := http.NewRequest("POST", "http://localhost:8080/order", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if language > "" {
+			req.Header.Set("Accept-Language", language)
+		}
+		return req
+	}
+
+	res := v.Parse(newRequest("1.234,56", "de-DE,de;q=0.9,en;q=0.8"))
+	assert.True(res.IsValid())
+	assert.Equal(1234.56, res.GetField("amount").Get())
+
+	res = v.Parse(newRequest("1 234,56", "xx, fr;q=0.5, en;q=0.1"))
+	assert.True(res.IsValid())
+	assert.Equal(1234.56, res.GetField("amount").Get())
+
+	res = v.Parse(newRequest("1,234.56", ""))
+	assert.True(res.IsValid())
+	assert.Equal(1234.56, res.GetField("amount").Get())
+
+	req := newRequest("2,5", "de")
+	res = u.Request().Body(v).Parse(req)
+	assert.True(res.IsValid())
+	assert.Equal(2.5, res.GetField("amount").Get())
+}
@@ -210,3 +210,77 @@ func TestUnmarshal(t *testing.T) {
 		assert.Equal(t, 5, tgt.Count)
 	})
 }
+
+func TestObjectQueryMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	newRequest := func(method, query, body string) *http.Request {
+		req, _ := http.NewRequest(method, "http://localhost:8080/items"+query, strings.NewReader(body))
+		if body > "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req
+	}
+
+	parse := func(req *http.Request, opts ...any) u.ObjectParseResult {
+		return u.Object(opts...).
+			String("Name").
+			Bool("dryRun").
+			Parse(req)
+	}
+
+	t.Run("body wins", func(t *testing.T) {
+		res := parse(newRequest("POST", "?dryRun=true&Name=query", `{ "Name": "body" }`))
+		assert.True(res.IsValid())
+		assert.True(res.GetBool("dryRun"))
+		assert.Equal("body", res.GetString("Name"))
+	})
+
+	t.Run("query wins", func(t *testing.T) {
+		res := parse(newRequest("POST", "?Name=query", `{ "Name": "body" }`), u.WithQueryMerge(u.QueryWins))
+		assert.True(res.IsValid())
+		assert.Equal("query", res.GetString("Name"))
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		res := parse(newRequest("POST", "?Name=query", `{ "Name": "body" }`), u.WithQueryMerge(u.QueryConflictError))
+		assert.False(res.IsValid())
+		assert.Equal("query parameter conflicts with request body", res.Errors()[0].Error())
+		assert.Equal("Name", res.Errors()[0].Path())
+
+		// every conflicting key is reported and the path doesn't depend on map ordering
+		for i := 0; i < 10; i++ {
+			res = parse(newRequest("POST", "?dryRun=true&Name=query", `{ "Name": "body", "dryRun": false }`), u.WithQueryMerge(u.QueryConflictError))
+			assert.False(res.IsValid())
+			assert.Equal("Name", res.Errors()[0].Path())
+			assert.Equal([]string{"Name", "dryRun"}, res.Errors()[0].Params()["keys"])
+		}
+	})
+
+	t.Run("content type without body", func(t *testing.T) {
+		req, _ := http.NewRequest("DELETE", "http://localhost:8080/items?Name=query", http.NoBody)
+		req.Header.Set("Content-Type", "application/json")
+		res := parse(req)
+		assert.True(res.IsValid())
+		assert.Equal("query", res.GetString("Name"))
+
+		req, _ = http.NewRequest("POST", "http://localhost:8080/items?Name=query", strings.NewReader(""))
+		req.Header.Set("Content-Type", "application/json")
+		res = parse(req)
+		assert.True(res.IsValid())
+		assert.Equal("query", res.GetString("Name"))
+	})
+
+	t.Run("ignored", func(t *testing.T) {
+		res := parse(newRequest("POST", "?dryRun=true", `{ "Name": "body" }`), u.WithQueryMerge(u.QueryIgnored))
+		assert.True(res.IsValid())
+		assert.False(res.GetBool("dryRun"))
+	})
+
+	t.Run("delete without body", func(t *testing.T) {
+		res := parse(newRequest("DELETE", "?Name=query&dryRun=true", ""))
+		assert.True(res.IsValid())
+		assert.Equal("query", res.GetString("Name"))
+		assert.True(res.GetBool("dryRun"))
+	})
+}
@@ -0,0 +1,192 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.String(u.Password(u.NISTPasswordPolicy()))
+	assert.True(v.Parse("correct horse battery staple").IsValid())
+	assert.True(v.Parse("Tr0ub4dor&3x").IsValid())
+
+	testCases := []struct {
+		password string
+		code     string
+	}{
+		{"short", "password_too_short"},
+		{strings.Repeat("a", 65), "password_too_long"},
+		{"password", "password_common"},
+		{"P@ssw0rd", "password_common"},
+		{"qwertyuiop123", "password_too_weak"},
+		{"aaaaaaaaaaaa", "password_too_weak"},
+		{"abcdefgh1234", "password_too_weak"},
+	}
+	for _, tc := range testCases {
+		res := v.Parse(tc.password)
+		if assert.False(res.IsValid(), tc.password) {
+			assert.Equal(tc.code, res.Errors()[0].Code(), tc.password)
+		}
+	}
+
+	classes := u.String(u.Password(u.PasswordPolicy{MinLength: 8, RequireUpper: true, RequireDigit: true, RequireSymbol: true}))
+	assert.True(classes.Parse("Secure#Pass9").IsValid())
+	assert.Equal("password_missing_upper", classes.Parse("secure#pass9").Errors()[0].Code())
+	assert.Equal("password_missing_digit", classes.Parse("Secure#Pass").Errors()[0].Code())
+	assert.Equal("password_missing_symbol", classes.Parse("SecurePass9").Errors()[0].Code())
+
+	site := u.String(u.Password(u.PasswordPolicy{MinLength: 8, UserInputs: []string{"acmewidgets"}}))
+	assert.Equal("password_contains_user_data", site.Parse("myacmewidgetspass").Errors()[0].Code())
+
+	// the message replaces the default but the code is kept
+	errs := u.String(u.Password(u.NISTPasswordPolicy(), "choose a stronger password")).Parse("password").Errors()
+	if assert.Equal(1, len(errs)) {
+		assert.Equal("choose a stronger password", errs[0].Error())
+		assert.Equal("password_common", errs[0].Code())
+	}
+}
+
+func TestPasswordStrength(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(0, u.EstimatePasswordStrength("password").Score)
+	assert.Equal(0, u.EstimatePasswordStrength("123456789").Score)
+	assert.Less(u.EstimatePasswordStrength("qwerty2019").Score, 3)
+	assert.Equal(4, u.EstimatePasswordStrength("correct horse battery staple").Score)
+	assert.Less(u.EstimatePasswordStrength("johnsmith99", "john smith").Entropy, u.EstimatePasswordStrength("johnsmith99").Entropy)
+	assert.Equal(0, u.EstimatePasswordStrength("").Score)
+
+	// only the start of a long password is matched so the cost doesn't grow with the square of the length
+	long := strings.Repeat("password", 10000)
+	start := time.Now()
+	assert.Equal(4, u.EstimatePasswordStrength(long).Score)
+	assert.Less(time.Since(start), time.Second)
+	assert.Greater(u.EstimatePasswordStrength(long+"x").Entropy, u.EstimatePasswordStrength(long).Entropy)
+}
+
+func TestPasswordHardMaxLength(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.String(u.Password(u.PasswordPolicy{MinLength: 12, MinScore: 3}))
+	res := v.Parse(strings.Repeat("a", u.MaxPasswordLength+1))
+	assert.False(res.IsValid())
+	assert.Equal("password_too_long", res.Errors()[0].Code())
+	assert.Equal(u.MaxPasswordLength, res.Errors()[0].Params()["max"])
+}
+
+func TestPasswordExcludesFields(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Object().
+		String("name").
+		String("email", u.Email(u.BareAddress())).
+		String("password", u.Password(u.NISTPasswordPolicy())).
+		Refine(u.PasswordExcludesFields("password", "name", "email"))
+
+	res := v.Parse(map[string]any{"name": "Jane Doe", "email": "jane.doe@example.com", "password": "blue-kettle-orbit"})
+	assert.True(res.IsValid())
+
+	res = v.Parse(map[string]any{"name": "Jane Doe", "email": "jdoe@example.com", "password": "JaneRocks!2024x"})
+	assert.False(res.IsValid())
+	assert.Equal("password contains personal information", res.GetError("password"))
+	assert.Equal(map[string]any{"field": "name"}, res.Errors()[0].Params())
+
+	res = v.Parse(map[string]any{"name": "Al", "email": "zebra.crossing@example.com", "password": "my zebra is fast"})
+	assert.False(res.IsValid())
+	assert.Equal(map[string]any{"field": "email"}, res.Errors()[0].Params())
+
+	// the domain of an email address and short words such as initials are ignored
+	res = v.Parse(map[string]any{"name": "Al Li", "email": "al.li@example.com", "password": "example-comet-alliance"})
+	assert.True(res.IsValid())
+}
+
+func TestPasswordBreachCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	breached := "blue-kettle-orbit"
+	sum := sha1.Sum([]byte(breached))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	requested := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if r.URL.Path == "/range/"+hash[:5] {
+			fmt.Fprintf(w, "0018A45C4D1DEF81644B54AB7F969B88D65:0\r\n%s:42\r\n", hash[5:])
+			return
+		}
+		fmt.Fprint(w, "0018A45C4D1DEF81644B54AB7F969B88D65:3\r\n")
+	}))
+	defer server.Close()
+
+	checker := &u.HIBPChecker{BaseURL: server.URL, Client: server.Client()}
+	v := u.String(u.Password(u.PasswordPolicy{MinLength: 8, BreachChecker: checker}))
+
+	res := v.Parse(breached)
+	assert.False(res.IsValid())
+	assert.Equal("password_breached", res.Errors()[0].Code())
+	assert.Equal([]string{"/range/" + hash[:5]}, requested)
+
+	assert.True(v.Parse("violet-sparrow-anchor").IsValid())
+
+	// the password is rejected when the service can't be reached unless errors are allowed
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	failing := &u.HIBPChecker{BaseURL: down.URL, Client: down.Client()}
+	res = u.String(u.Password(u.PasswordPolicy{MinLength: 8, BreachChecker: failing})).Parse("violet-sparrow-anchor")
+	assert.False(res.IsValid())
+	assert.Equal("password_check_failed", res.Errors()[0].Code())
+
+	res = u.String(u.Password(u.PasswordPolicy{MinLength: 8, BreachChecker: failing, AllowOnBreachCheckError: true})).Parse("violet-sparrow-anchor")
+	assert.True(res.IsValid())
+
+	// a slow service is abandoned after the checker's timeout
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+
+	start := time.Now()
+	timeout := &u.HIBPChecker{BaseURL: slow.URL, Client: slow.Client(), Timeout: 50 * time.Millisecond}
+	res = u.String(u.Password(u.PasswordPolicy{MinLength: 8, BreachChecker: timeout})).Parse("violet-sparrow-anchor")
+	assert.Equal("password_check_failed", res.Errors()[0].Code())
+	assert.Less(time.Since(start), 500*time.Millisecond)
+
+	start = time.Now()
+	timeout.Timeout = 0
+	res = u.String(u.Password(u.PasswordPolicy{MinLength: 8, BreachChecker: timeout, BreachCheckTimeout: 50 * time.Millisecond})).Parse("violet-sparrow-anchor")
+	assert.Equal("password_check_failed", res.Errors()[0].Code())
+	assert.Less(time.Since(start), 500*time.Millisecond)
+}
@@ -0,0 +1,78 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Request().
+		Path(u.Object().Int("id", u.Min(1), u.Required())).
+		Query(u.Object().Bool("dryRun", u.WithDefault(false))).
+		Header(u.Object().String("X-Api-Key", u.MinLength(8), u.Required())).
+		Cookie(u.Object().String("locale", u.Enum("en", "de"))).
+		Body(u.Object().String("name", u.Required()))
+
+	newRequest := func(id, query, key, locale, body string) *http.Request {
+		req, _ := http.NewRequest("POST", "http://localhost:8080/items/"+id+query, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.SetPathValue("id", id)
+		if key > "" {
+			req.Header.Set("X-Api-Key", key)
+		}
+		req.AddCookie(&http.Cookie{Name: "locale", Value: locale})
+		return req
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		res := v.Parse(newRequest("42", "?dryRun=true", "secret-key", "de", `{"name": "widget"}`))
+		assert.True(res.IsValid())
+		assert.Equal(42, res.GetInt("id"))
+		assert.True(res.GetBool("dryRun"))
+		assert.Equal("secret-key", res.GetString("X-Api-Key"))
+		assert.Equal("de", res.GetString("locale"))
+		assert.Equal("widget", res.GetString("name"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		res := v.Parse(newRequest("0", "", "", "fr", `{}`))
+		assert.False(res.IsValid())
+
+		paths := make([]string, 0)
+		for _, err := range res.Errors() {
+			paths = append(paths, err.Path())
+		}
+		assert.ElementsMatch([]string{"path.id", "header.X-Api-Key", "cookie.locale", "body.name"}, paths)
+		assert.Equal("number too small", res.GetError("id"))
+	})
+
+	t.Run("duplicate field", func(t *testing.T) {
+		v := u.Request().
+			Path(u.Object().Int("id")).
+			Query(u.Object().Int("id"))
+		assert.Error(v.Error())
+		assert.False(v.Parse(newRequest("1", "", "", "", "{}")).IsValid())
+	})
+}
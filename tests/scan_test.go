@@ -0,0 +1,148 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClamd implements just enough of the clamd INSTREAM protocol to test the scanner
+func fakeClamd(t *testing.T, delay time.Duration) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				cmd, err := r.ReadString(0)
+				if err != nil || cmd != "zINSTREAM\x00" {
+					_, _ = conn.Write([]byte("UNKNOWN COMMAND\x00"))
+					return
+				}
+				content := new(bytes.Buffer)
+				for {
+					var size uint32
+					if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+						return
+					}
+					if size == 0 {
+						break
+					}
+					if _, err := io.CopyN(content, r, int64(size)); err != nil {
+						return
+					}
+				}
+				time.Sleep(delay)
+				if strings.Contains(content.String(), "EICAR") {
+					_, _ = conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+					return
+				}
+				_, _ = conn.Write([]byte("stream: OK\x00"))
+			}(conn)
+		}
+	}()
+
+	return l.Addr().String()
+}
+
+func uploadRequest(content string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	fileWriter, _ := writer.CreateFormFile("file", "test.txt")
+	_, _ = fileWriter.Write([]byte(content))
+	writer.Close()
+
+	req, _ := http.NewRequest("POST", "http://localhost:8080/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestScanWith(t *testing.T) {
+	assert := assert.New(t)
+
+	scanner := u.NewClamAVScanner("tcp", fakeClamd(t, 0))
+	scanner.ChunkSize = 4
+
+	v := u.Object(u.WithMaxBodySize(1000)).
+		File("file", u.ScanWith(scanner))
+
+	t.Run("clean", func(t *testing.T) {
+		res := v.Parse(uploadRequest("a perfectly harmless file"))
+		assert.True(res.IsValid())
+		assert.Equal(0, len(res.Errors()))
+	})
+
+	t.Run("infected", func(t *testing.T) {
+		res := v.Parse(uploadRequest("X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR"))
+		assert.False(res.IsValid())
+		assert.Equal("file failed malware scan", res.GetError("file"))
+		assert.Equal(1, len(res.GetField("file").Errors()[0].Inner()))
+	})
+}
+
+func TestScanTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	scanner := u.NewClamAVScanner("tcp", fakeClamd(t, 500*time.Millisecond))
+
+	v := u.Object(u.WithMaxBodySize(1000)).
+		File("file", u.ScanWithTimeout(scanner, 50*time.Millisecond, "scan failed"))
+
+	res := v.Parse(uploadRequest("a perfectly harmless file"))
+	assert.False(res.IsValid())
+	assert.Equal("scan failed", res.GetError("file"))
+}
+
+func TestScanRequestCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	scanner := u.NewClamAVScanner("tcp", fakeClamd(t, 500*time.Millisecond))
+
+	v := u.Object(u.WithMaxBodySize(1000)).
+		File("file", u.ScanWith(scanner, "scan failed"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	res := v.Parse(uploadRequest("a perfectly harmless file").WithContext(ctx))
+	assert.False(res.IsValid())
+	assert.Equal("scan failed", res.GetError("file"))
+	assert.Less(time.Since(start), 400*time.Millisecond)
+}
@@ -0,0 +1,95 @@
+package tests
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"strings"
+	"testing"
+
+	u "github.com/jdudmesh/ursa"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStream(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Object().
+		String("sku", u.MinLength(3), u.Required()).
+		Float64("price", u.Min(0))
+
+	t.Run("ndjson", func(t *testing.T) {
+		data := `{"sku": "abc", "price": 1.5}
+{"sku": "x", "price": 2}
+
+{"sku": "def", "price": -1}
+not json
+{"sku": "ghi", "price": 3}
+`
+		invalid := make(map[int]string)
+		count := 0
+		for line, res := range v.ParseStream(strings.NewReader(data)) {
+			count++
+			if !res.IsValid() {
+				invalid[line] = res.Errors()[0].Error()
+			}
+		}
+		assert.Equal(5, count)
+		assert.Equal(map[int]string{
+			2: "string too short",
+			4: "number too small",
+			5: "unmarshalling JSON value",
+		}, invalid)
+	})
+
+	t.Run("array", func(t *testing.T) {
+		data := `
+[
+  {"sku": "abc", "price": 1.5},
+  {
+    "sku": "x",
+    "price": 2
+  },
+  {"sku": "def", "price": -1}
+]`
+		lines := make([]int, 0)
+		valid := make([]bool, 0)
+		for line, res := range v.ParseStream(strings.NewReader(data)) {
+			lines = append(lines, line)
+			valid = append(valid, res.IsValid())
+		}
+		assert.Equal([]int{3, 4, 8}, lines)
+		assert.Equal([]bool{true, false, false}, valid)
+	})
+
+	t.Run("malformed array", func(t *testing.T) {
+		data := `[{"sku": "abc"}, {"sku": `
+		valid := make([]bool, 0)
+		for _, res := range v.ParseStream(strings.NewReader(data)) {
+			valid = append(valid, res.IsValid())
+		}
+		assert.Equal([]bool{true, false}, valid)
+	})
+
+	t.Run("stop after errors", func(t *testing.T) {
+		data := strings.Repeat(`{"sku": "x"}`+"\n", 10)
+		count := 0
+		for range v.ParseStream(strings.NewReader(data), u.StopAfterErrors(3)) {
+			count++
+		}
+		assert.Equal(3, count)
+	})
+}
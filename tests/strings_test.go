@@ -112,3 +112,107 @@ func TestStringDefault(t *testing.T) {
 		assert.Equal("01234678", res.Get())
 	})
 }
+
+func TestStringUnicode(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(u.String(u.MaxLength(4)).Parse("José").IsValid())
+	assert.False(u.String(u.MaxBytes(4)).Parse("José").IsValid())
+
+	family := "\U0001F468\u200d\U0001F469\u200d\U0001F467\u200d\U0001F466"
+	assert.False(u.String(u.MaxLength(1)).Parse(family).IsValid())
+	assert.True(u.String(u.MaxLengthIn(u.Graphemes, 1)).Parse(family).IsValid())
+	errs := u.String(u.MinLengthIn(u.Graphemes, 2)).Parse(family).Errors()
+	assert.Equal("string too short", errs[0].Error())
+	assert.Equal(map[string]any{"min": 2}, errs[0].Params())
+
+	decomposed := "Jose\u0301"
+	res := u.String(u.NormalizeNFC(), u.MaxLength(4)).Parse(decomposed)
+	assert.True(res.IsValid())
+	assert.Equal("José", res.Get())
+	assert.Equal("fi12", u.String(u.NormalizeNFKC()).Parse("\ufb01\uff11\uff12").Get())
+
+	assert.True(u.String(u.NoControlChars()).Parse("line one\nline two").IsValid())
+	assert.False(u.String(u.NoControlChars()).Parse("bell\a").IsValid())
+	assert.False(u.String(u.NoControlChars()).Parse("bad\xff").IsValid())
+
+	assert.True(u.String(u.NoInvisibleChars()).Parse(family).IsValid())
+	assert.False(u.String(u.NoInvisibleChars()).Parse("admin\u200b").IsValid())
+	assert.False(u.String(u.NoInvisibleChars()).Parse("\u202egnp.exe").IsValid())
+	assert.False(u.String(u.NoInvisibleChars()).Parse("\u3164").IsValid())
+
+	assert.True(u.String(u.PrintableOnly()).Parse("Zoë Ω 👍").IsValid())
+	errs = u.String(u.PrintableOnly()).Parse("tab\there").Errors()
+	assert.Equal("string contains non printable characters", errs[0].Error())
+}
+
+func TestStringCodes(t *testing.T) {
+	assert := assert.New(t)
+
+	country := u.String(u.CountryCode())
+	assert.True(country.Parse("GB").IsValid())
+	for _, val := range []string{"UK", "gb", "GBR", "XX", "EU", " GB"} {
+		errs := country.Parse(val).Errors()
+		assert.Equal(1, len(errs), val)
+		assert.Equal("invalid_country", errs[0].Code(), val)
+	}
+
+	country = u.String(u.CountryCode(u.Canonical()))
+	for _, val := range []string{"GB", "uk", "gbr", " gb "} {
+		res := country.Parse(val)
+		assert.True(res.IsValid(), val)
+		assert.Equal("GB", res.Get(), val)
+	}
+	assert.False(country.Parse("XK").IsValid())
+
+	country = u.String(u.CountryCode(u.Canonical(), u.Alpha3(), "choose a country"))
+	res := country.Parse("uk")
+	assert.Equal("GBR", res.Get())
+	errs := country.Parse("Narnia").Errors()
+	assert.Equal("choose a country", errs[0].Error())
+	assert.Equal("Narnia", errs[0].Params()["value"])
+	assert.True(u.String(u.CountryCode(u.Alpha3())).Parse("DEU").IsValid())
+	assert.False(u.String(u.CountryCode(u.Alpha3())).Parse("DE").IsValid())
+
+	currency := u.String(u.CurrencyCode())
+	assert.True(currency.Parse("EUR").IsValid())
+	assert.False(currency.Parse("eur").IsValid())
+	assert.False(currency.Parse("XYZ").IsValid())
+	res = u.String(u.CurrencyCode(u.Canonical())).Parse("jpy")
+	assert.Equal("JPY", res.Get())
+
+	lang := u.String(u.LanguageCode())
+	assert.True(lang.Parse("en").IsValid())
+	assert.False(lang.Parse("eng").IsValid())
+	assert.False(lang.Parse("xx").IsValid())
+	assert.False(lang.Parse("und").IsValid())
+	res = u.String(u.LanguageCode(u.Canonical())).Parse("ENG")
+	assert.Equal("en", res.Get())
+	res = u.String(u.LanguageCode(u.Canonical(), u.Alpha3())).Parse("fr")
+	assert.Equal("fra", res.Get())
+
+	tag := u.String(u.LanguageTag())
+	assert.True(tag.Parse("en-GB").IsValid())
+	assert.True(tag.Parse("zh-Hant-TW").IsValid())
+	assert.False(tag.Parse("en-gb").IsValid())
+	assert.False(tag.Parse("english").IsValid())
+	res = u.String(u.LanguageTag(u.Canonical())).Parse("en_gb")
+	assert.Equal("en-GB", res.Get())
+	errs = tag.Parse("und").Errors()
+	assert.Equal("invalid_language_tag", errs[0].Code())
+
+	tz := u.String(u.TimeZone())
+	assert.True(tz.Parse("Europe/London").IsValid())
+	assert.True(tz.Parse("UTC").IsValid())
+	for _, val := range []string{"", "Local", "Mars/Olympus_Mons", "GMT+25"} {
+		errs := tz.Parse(val).Errors()
+		assert.Equal(1, len(errs), val)
+		assert.Equal("invalid_time_zone", errs[0].Code(), val)
+	}
+	tz = u.String(u.TimeZone(u.Canonical()))
+	res = tz.Parse("america/new_york")
+	assert.True(res.IsValid())
+	assert.Equal("America/New_York", res.Get())
+	res = tz.Parse("utc")
+	assert.Equal("UTC", res.Get())
+}
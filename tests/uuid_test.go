@@ -17,7 +17,9 @@ package tests
 // along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	u "github.com/jdudmesh/ursa"
@@ -48,3 +50,123 @@ func TestUUID(t *testing.T) {
 	assert.False(res.IsValid())
 	assert.Equal("uuid is zero", res.Errors()[0].Error())
 }
+
+func TestUUIDForms(t *testing.T) {
+	assert := assert.New(t)
+
+	id := uuid.MustParse("f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	v := u.UUID()
+
+	for _, val := range []any{
+		"{f47ac10b-58cc-4372-a567-0e02b2c3d479}",
+		"urn:uuid:f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		"F47AC10B58CC4372A5670E02B2C3D479",
+		[16]byte(id),
+		id[:],
+		[]byte(id.String()),
+		uuid.NullUUID{UUID: id, Valid: true},
+		&id,
+	} {
+		res := v.Parse(val)
+		assert.True(res.IsValid(), val)
+		assert.Equal(id, res.Get(), val)
+	}
+
+	res := v.Parse(uuid.NullUUID{})
+	assert.Equal(uuid.Nil, res.Get())
+	assert.False(u.UUID(u.NonNullUUID()).Parse(uuid.NullUUID{}).IsValid())
+
+	for _, val := range []any{"f47ac10b", []byte{1, 2, 3}, 42} {
+		assert.False(v.Parse(val).IsValid(), val)
+	}
+	errs := v.Parse("not-a-uuid").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("invalid_uuid", errs[0].Code())
+}
+
+func TestUUIDVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.UUID(u.UUIDVersion(7), u.UUIDVariant(uuid.RFC4122))
+
+	v7, err := uuid.NewV7()
+	assert.NoError(err)
+	assert.True(v.Parse(v7.String()).IsValid())
+
+	errs := v.Parse(uuid.New()).Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("invalid_uuid_version", errs[0].Code())
+	assert.Equal(4, errs[0].Params()["version"])
+	assert.Equal([]int{7}, errs[0].Params()["versions"])
+
+	assert.True(u.UUID(u.UUIDVersion(4, 7)).Parse(uuid.New()).IsValid())
+
+	// the variant bits of a Microsoft GUID are 110
+	errs = v.Parse("01890a5d-ac96-774b-dcce-b302099a8057").Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("invalid_uuid_variant", errs[0].Code())
+}
+
+func TestUUIDTime(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.UUID(u.UUIDNotInFuture(time.Minute))
+
+	v7, _ := uuid.NewV7()
+	v6, _ := uuid.NewV6()
+	v1, _ := uuid.NewUUID()
+	for _, id := range []uuid.UUID{v7, v6, v1} {
+		assert.True(v.Parse(id).IsValid(), id.Version())
+	}
+
+	// a v7 id from the year 6429
+	future := uuid.MustParse("7ffffff0-0000-7000-8000-000000000000")
+	errs := v.Parse(future).Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("id_in_future", errs[0].Code())
+
+	assert.False(v.Parse(uuid.New()).IsValid())
+
+	// the clock can be replaced in the same way as for times
+	clock := u.ClockFunc(func() time.Time { return time.Date(9000, 1, 1, 0, 0, 0, 0, time.UTC) })
+	assert.True(u.UUID(u.UUIDNotInFuture(0), u.WithClock(clock)).Parse(future).IsValid())
+	clock = u.ClockFunc(func() time.Time { return time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC) })
+	assert.False(u.Object().UUID("id", u.WithClock(clock), u.UUIDNotInFuture(time.Minute)).Parse(map[string]any{"id": v7}).IsValid())
+}
+
+func TestUUIDDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.UUID(u.WithDefaultFunc(uuid.NewV7))
+	first := v.Parse(nil)
+	second := v.Parse(nil)
+	assert.True(first.IsValid())
+	assert.Equal(uuid.Version(7), first.Get().Version())
+	assert.NotEqual(first.Get(), second.Get())
+
+	v = u.UUID(u.WithDefaultFunc(func() (uuid.UUID, error) {
+		return uuid.Nil, errors.New("no entropy")
+	}))
+	errs := v.Parse(nil).Errors()
+	assert.Equal(1, len(errs))
+	assert.Equal("failed to generate default value", errs[0].Error())
+
+	type item struct {
+		ID   uuid.UUID `json:"id"`
+		Name string    `json:"name"`
+	}
+	schema := u.Object().
+		UUID("id", u.WithDefaultFunc(uuid.NewV7)).
+		String("name")
+
+	res := schema.Parse([]byte(`{"name":"widget"}`))
+	assert.True(res.IsValid())
+	i := item{}
+	assert.NoError(res.Unmarshal(&i))
+	assert.Equal(uuid.Version(7), i.ID.Version())
+
+	id := uuid.New()
+	res = schema.Parse([]byte(`{"id":"` + id.String() + `","name":"widget"}`))
+	assert.NoError(res.Unmarshal(&i))
+	assert.Equal(id, i.ID)
+}
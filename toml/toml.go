@@ -0,0 +1,105 @@
+// Package toml registers a TOML body decoder with ursa, import it for its side effects:
+//
+//	import _ "github.com/jdudmesh/ursa/toml"
+package toml
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"bytes"
+
+	"github.com/BurntSushi/toml"
+	u "github.com/jdudmesh/ursa"
+)
+
+func init() {
+	for _, mediaType := range []string{"application/toml", "text/toml"} {
+		u.RegisterDecoder(mediaType, Decode)
+	}
+}
+
+// Decode reads a TOML document, the nesting of inline arrays and tables is checked first because the parser
+// has no limit of its own
+func Decode(data []byte, maxDepth int) (map[string]interface{}, error) {
+	if err := checkDepth(data, maxDepth); err != nil {
+		return nil, err
+	}
+
+	unpacked := make(map[string]interface{})
+	if err := toml.Unmarshal(data, &unpacked); err != nil {
+		return nil, err
+	}
+	return unpacked, nil
+}
+
+// checkDepth counts the brackets of inline arrays and tables outside of strings and comments,
+// table headers e.g. [[items]] are skipped as they don't nest
+func checkDepth(data []byte, maxDepth int) error {
+	depth := 1
+	lineStart := true
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '\n':
+			lineStart = true
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			continue
+		case c == '#' || (c == '[' && lineStart && depth == 1):
+			i = skipLine(data, i)
+		case c == '"' || c == '\'':
+			i = skipString(data, i)
+		case c == '[' || c == '{':
+			depth++
+			if depth > maxDepth {
+				return u.ErrDecodeTooDeep
+			}
+		case c == ']' || c == '}':
+			depth--
+		}
+		lineStart = false
+	}
+	return nil
+}
+
+// skipLine returns the index of the last byte before the end of the line
+func skipLine(data []byte, i int) int {
+	if ix := bytes.IndexByte(data[i:], '\n'); ix >= 0 {
+		return i + ix - 1
+	}
+	return len(data) - 1
+}
+
+// skipString returns the index of the closing quote, basic strings may contain escaped quotes
+func skipString(data []byte, i int) int {
+	quote := data[i]
+	delim := data[i : i+1]
+	if bytes.HasPrefix(data[i:], []byte{quote, quote, quote}) {
+		delim = data[i : i+3]
+	}
+	for j := i + len(delim); j < len(data); j++ {
+		switch {
+		case data[j] == '\\' && quote == '"':
+			j++
+		case len(delim) == 1 && data[j] == '\n':
+			return j - 1
+		case bytes.HasPrefix(data[j:], delim):
+			return j + len(delim) - 1
+		}
+	}
+	return len(data) - 1
+}
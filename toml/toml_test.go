@@ -0,0 +1,63 @@
+package toml_test
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"strings"
+	"testing"
+
+	u "github.com/jdudmesh/ursa"
+	_ "github.com/jdudmesh/ursa/toml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Object().
+		String("Name", u.MinLength(5)).
+		Int("Count", u.Min(1)).
+		Object("Address", u.Object().String("City", u.Required()))
+
+	res := v.ParseFormat([]byte("Name = \"abcdef\"\nCount = 5\n[Address]\nCity = \"Leeds\"\n"), "application/toml")
+	assert.True(res.IsAllValid())
+	assert.Equal("abcdef", res.GetString("Name"))
+	assert.Equal(5, res.GetInt("Count"))
+}
+
+func TestDecodeDepth(t *testing.T) {
+	assert := assert.New(t)
+
+	// the parser has no limit of its own and takes minutes over deeply nested inline tables
+	deep := []byte("a = " + strings.Repeat("{b = ", 20000) + "1" + strings.Repeat("}", 20000))
+	res := u.Object().ParseFormat(deep, "application/toml")
+	assert.False(res.IsValid())
+	assert.ErrorIs(res.Errors()[0], u.DocumentTooDeepError)
+
+	limited := u.Object(u.MaxJSONDepth(2)).String("Name")
+	for _, doc := range []string{"a = [[1]]\n", "[a.b]\nc = 1\n"} {
+		res = limited.ParseFormat([]byte(doc), "application/toml")
+		assert.False(res.IsValid(), doc)
+		assert.ErrorIs(res.Errors()[0], u.DocumentTooDeepError, doc)
+	}
+
+	// table headers and brackets inside strings and comments don't count towards the depth
+	res = limited.ParseFormat([]byte("[[items]]\nName = \"[[[\" # {{{\n"), "application/toml")
+	assert.True(res.IsValid())
+	res = limited.ParseFormat([]byte("Name = \"\"\"\n[[[\\\"\"\"\"\n"), "application/toml")
+	assert.True(res.IsValid())
+}
@@ -1,7 +1,12 @@
 package ursa
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -29,6 +34,7 @@ type validator[T any] struct {
 	transformerFn   transformer[T]
 	options         []parseOpt[T]
 	defaultValue    *T
+	defaultFunc     func() (any, error)
 	required        bool
 	requiredMessage string
 	err             error
@@ -52,7 +58,9 @@ type genericValidatorOptReceiver interface {
 	hasTransformer() bool
 	setTransformer(fn transformer[any])
 	setDefault(val any)
+	setDefaultFunc(fn func() (any, error))
 	setRequired(message ...string)
+	addOption(fn func(val any) (any, *parseError))
 }
 
 type validatorWithOpts[T any] interface {
@@ -62,6 +70,11 @@ type validatorWithOpts[T any] interface {
 
 type genericValidatorOpt func(v genericValidatorOptReceiver) error
 
+// configurableOpt is implemented by options which carry their own settings e.g. EnumOf(...).CaseInsensitive()
+type configurableOpt interface {
+	apply(v genericValidatorOptReceiver) error
+}
+
 type parseResult[T any] struct {
 	valid  bool
 	value  T
@@ -71,6 +84,10 @@ type parseResult[T any] struct {
 type parseError struct {
 	message string
 	inner   []error
+	path    string
+	params  map[string]any
+	code    string
+	origin  *parseError // the shared error this one was copied from
 }
 
 func (r *parseResult[T]) IsValid() bool {
@@ -102,6 +119,39 @@ func (e *parseError) Error() string {
 	return e.message
 }
 
+// Path returns the location of the value which caused the error e.g. "query.page"
+func (e *parseError) Path() string {
+	return e.path
+}
+
+// Params returns the values used by the check which failed e.g. the bound for Min, it may be nil
+func (e *parseError) Params() map[string]any {
+	return e.params
+}
+
+// Code identifies the check which failed e.g. "invalid_url" so callers can translate the message, it may be empty
+func (e *parseError) Code() string {
+	return e.code
+}
+
+// withPath returns a copy of the error with the path set, the shared error values must never be modified
+func (e *parseError) withPath(path string) *parseError {
+	return &parseError{message: e.message, inner: e.inner, path: path, params: e.params, code: e.code, origin: e.root()}
+}
+
+// Is reports whether the error is a copy of target so errors.Is works with the exported error values
+func (e *parseError) Is(target error) bool {
+	other, ok := target.(*parseError)
+	return ok && e.root() == other
+}
+
+func (e *parseError) root() *parseError {
+	if e.origin != nil {
+		return e.origin
+	}
+	return e
+}
+
 var InvalidTypeError = &parseError{
 	message: "invalid type",
 }
@@ -111,18 +161,38 @@ var InvalidValueError = &parseError{
 }
 
 var InvalidValidatorStateError = &parseError{
-	message: "invalid type",
+	message: "invalid validator",
+	code:    "invalid_validator",
+}
+
+// validatorStateError copies InvalidValidatorStateError with the reason the validator is unusable e.g. an option
+// which doesn't apply to it, so the problem with the schema can't be mistaken for a problem with the input
+func validatorStateError(err error) *parseError {
+	state := InvalidValidatorStateError
+	return &parseError{message: state.message + ": " + err.Error(), inner: []error{err}, code: state.code, origin: state}
 }
 
 var MissingTransformerError = &parseError{
 	message: "missing property transformer",
 }
 
+var NumberOutOfRangeError = &parseError{
+	message: "number out of range",
+}
+
+var InexactNumberError = &parseError{
+	message: "number cannot be represented exactly",
+}
+
+var UnsupportedContentTypeError = &parseError{
+	message: "unsupported content type",
+}
+
 func (v *validator[T]) Parse(val any, opts ...parseOpt[T]) genericParseResult[T] {
 	res := &parseResult[T]{valid: true}
 	if v.err != nil {
 		res.valid = false
-		res.errors = []*parseError{InvalidValidatorStateError}
+		res.errors = []*parseError{validatorStateError(v.err)}
 		return res
 	}
 
@@ -162,6 +232,13 @@ func (v *validator[T]) convert(val any) (*T, *parseError) {
 			if v.defaultValue != nil {
 				return v.convert(v.defaultValue)
 			}
+			if v.defaultFunc != nil {
+				d, err := v.defaultFunc()
+				if err != nil {
+					return nil, &parseError{message: "failed to generate default value", inner: []error{err}}
+				}
+				return v.convert(d)
+			}
 			if v.required {
 				return nil, &parseError{message: v.requiredMessage}
 			}
@@ -169,41 +246,117 @@ func (v *validator[T]) convert(val any) (*T, *parseError) {
 		}
 	}
 
-	if vo.Kind() != reflect.TypeOf(typedVal).Kind() {
-		if v.transformerFn == nil {
-			if !isNumeric(val) && isNumeric(typedVal) {
-				val, err = coerceToNumber[float64](val)
-				if err != nil {
-					return nil, InvalidTypeError
+	if num, ok := val.(json.Number); ok && v.transformerFn == nil {
+		return convertJSONNumber[T](num)
+	}
+
+	if vo.Kind() == reflect.TypeOf(typedVal).Kind() {
+		if tv, ok := vo.Interface().(T); ok {
+			return &tv, nil
+		}
+	}
+
+	if v.transformerFn == nil {
+		if !isNumeric(val) && isNumeric(typedVal) {
+			if str, ok := vo.Interface().(string); ok && isInteger(typedVal) {
+				// parse integers directly so large values don't lose precision by passing through float64
+				if num, err := convertJSONNumber[T](json.Number(strings.TrimSpace(str))); err == nil {
+					return num, nil
 				}
 			}
-			if reflect.TypeOf(typedVal).Kind() == reflect.Bool && vo.Kind() == reflect.String {
-				val, err = coerceToBool(val.(string))
-				if err != nil {
-					return nil, InvalidTypeError
-				}
+			val, err = coerceToNumber[float64](val)
+			if err != nil || (isInteger(typedVal) && isNonFinite(val)) {
+				return nil, InvalidTypeError
 			}
-		} else {
-			val, err = v.transformerFn(val)
+		}
+		if reflect.TypeOf(typedVal).Kind() == reflect.Bool && vo.Kind() == reflect.String {
+			val, err = coerceToBool(vo.String())
 			if err != nil {
-				return nil, &parseError{message: "transformer error", inner: []error{err}}
+				return nil, InvalidTypeError
+			}
+		}
+	} else {
+		val, err = v.transformerFn(val)
+		if err != nil {
+			// transformers can report a specific validation error, anything else is wrapped
+			if perr, ok := err.(*parseError); ok && perr != InvalidValueError {
+				return nil, perr
+			}
+			return nil, &parseError{message: "transformer error", inner: []error{err}}
+		}
+	}
+
+	if reflect.TypeOf(val).ConvertibleTo(reflect.TypeOf(typedVal)) {
+		if v, ok := reflect.ValueOf(val).Convert(reflect.TypeOf(typedVal)).Interface().(T); ok {
+			typedVal = v
+		} else {
+			return nil, InvalidTypeError
+		}
+	} else {
+		return nil, MissingTransformerError
+	}
+
+	return &typedVal, nil
+}
+
+// convertJSONNumber converts the literal number to the target type without passing through float64 so that
+// large integers keep their precision, numbers which don't fit the target type are rejected
+func convertJSONNumber[T any](num json.Number) (*T, *parseError) {
+	var typedVal T
+	to := reflect.TypeOf(typedVal)
+	out := reflect.New(to).Elem()
+
+	switch to.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(num.String(), 10, to.Bits())
+		if err != nil {
+			r, ok := new(big.Rat).SetString(num.String())
+			switch {
+			case !ok:
+				return nil, InvalidTypeError
+			case !r.IsInt():
+				return nil, InexactNumberError
+			case !r.Num().IsInt64() || out.OverflowInt(r.Num().Int64()):
+				return nil, NumberOutOfRangeError
 			}
+			i = r.Num().Int64()
 		}
+		out.SetInt(i)
 
-		if reflect.TypeOf(val).ConvertibleTo(reflect.TypeOf(typedVal)) {
-			if v, ok := reflect.ValueOf(val).Convert(reflect.TypeOf(typedVal)).Interface().(T); ok {
-				typedVal = v
-			} else {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, err := strconv.ParseUint(num.String(), 10, to.Bits())
+		if err != nil {
+			r, ok := new(big.Rat).SetString(num.String())
+			switch {
+			case !ok:
 				return nil, InvalidTypeError
+			case !r.IsInt():
+				return nil, InexactNumberError
+			case !r.Num().IsUint64() || out.OverflowUint(r.Num().Uint64()):
+				return nil, NumberOutOfRangeError
 			}
-		} else {
-			return nil, MissingTransformerError
+			i = r.Num().Uint64()
 		}
+		out.SetUint(i)
 
-	} else {
-		typedVal = vo.Interface().(T)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(num.String(), to.Bits())
+		if errors.Is(err, strconv.ErrRange) {
+			return nil, NumberOutOfRangeError
+		}
+		if err != nil {
+			return nil, InvalidTypeError
+		}
+		out.SetFloat(f)
+
+	case reflect.String:
+		out.SetString(num.String())
+
+	default:
+		return nil, InvalidTypeError
 	}
 
+	typedVal = out.Interface().(T)
 	return &typedVal, nil
 }
 
@@ -212,6 +365,9 @@ func (b *validator[T]) setTransformer(fn transformer[any]) {
 		var zero T
 
 		val, err := fn(val)
+		if perr, ok := err.(*parseError); ok {
+			return zero, perr
+		}
 		if err != nil {
 			return zero, InvalidValueError
 		}
@@ -225,6 +381,26 @@ func (b *validator[T]) setTransformer(fn transformer[any]) {
 	}
 }
 
+// addOption adds a check which isn't tied to the field type, the value it returns replaces the field value
+func (b *validator[T]) addOption(fn func(val any) (any, *parseError)) {
+	b.options = append(b.options, func(val *T) *parseError {
+		if val == nil {
+			return nil
+		}
+		res, err := fn(*val)
+		if err != nil {
+			return err
+		}
+		var zero T
+		rv := reflect.ValueOf(res)
+		if !rv.IsValid() || !rv.Type().ConvertibleTo(reflect.TypeOf(zero)) {
+			return InvalidTypeError
+		}
+		*val = rv.Convert(reflect.TypeOf(zero)).Interface().(T)
+		return nil
+	})
+}
+
 func (b *validator[T]) hasTransformer() bool {
 	return b.transformerFn != nil
 }
@@ -251,6 +427,10 @@ func (b *validator[T]) setDefault(val any) {
 	b.defaultValue = &d
 }
 
+func (b *validator[T]) setDefaultFunc(fn func() (any, error)) {
+	b.defaultFunc = fn
+}
+
 func (b *validator[T]) setRequired(message ...string) {
 	b.required = true
 	if len(message) > 0 {
@@ -276,6 +456,16 @@ func WithDefault(val any) genericValidatorOpt {
 	}
 }
 
+// WithDefaultFunc generates a new default for each missing value e.g. WithDefaultFunc(uuid.NewV7)
+func WithDefaultFunc[T any](fn func() (T, error)) genericValidatorOpt {
+	return func(v genericValidatorOptReceiver) error {
+		v.setDefaultFunc(func() (any, error) {
+			return fn()
+		})
+		return nil
+	}
+}
+
 func Required(message ...string) genericValidatorOpt {
 	return func(v genericValidatorOptReceiver) error {
 		v.setRequired(message...)
@@ -288,15 +478,38 @@ func validatorFactory[T any](opts ...any) validatorWithOpts[T] {
 		options: make([]parseOpt[T], 0, len(opts)),
 	}
 
+	clock := SystemClock
+	for _, opt := range opts {
+		if opt, ok := opt.(clockOpt); ok {
+			clock = opt.clock
+		}
+	}
+
 	for _, opt := range opts {
 		switch opt := opt.(type) {
 		case parseOpt[T]:
 			v.options = append(v.options, opt)
+		case clockedOpt[T]:
+			v.options = append(v.options, func(val *T) *parseError {
+				if val == nil {
+					return nil
+				}
+				return opt(clock.Now(), *val)
+			})
+		case clockOpt:
 		case genericValidatorOpt:
 			err := opt(v)
 			if err != nil {
 				v.err = err
 			}
+		case configurableOpt:
+			if err := opt.apply(v); err != nil {
+				v.err = err
+			}
+		case nil:
+		default:
+			// e.g. a time option passed to a string validator, ignoring it would silently skip a check
+			v.err = fmt.Errorf("unsupported option %T for %s validator", opt, reflect.TypeFor[T]())
 		}
 	}
 
@@ -314,6 +527,16 @@ func isNumeric(i interface{}) bool {
 	}
 }
 
+func isInteger(i interface{}) bool {
+	switch reflect.TypeOf(i).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
 func extractTags(name string, field reflect.StructField) []string {
 	tagsMap := []string{name}
 	for _, tag := range []string{"json", "form", "query"} {
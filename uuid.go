@@ -17,31 +17,64 @@ package ursa
 // along with this program.  If not, see <https://www.gnu.org/licenses/>.
 
 import (
+	"encoding/binary"
 	"reflect"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type uuidValidatorOpt = parseOpt[uuid.UUID]
 
+var InvalidUUIDError = &parseError{message: "invalid uuid", code: "invalid_uuid"}
+
+// UUID parses the hyphenated, braced, URN and plain hex forms, the 16 byte binary form and uuid.NullUUID.
+// A NullUUID which isn't valid parses as the nil UUID so use NonNullUUID to reject it.
 func UUID(opts ...any) genericValidator[uuid.UUID] {
+	return uuidValidatorFactory(opts...)
+}
+
+func uuidValidatorFactory(opts ...any) validatorWithOpts[uuid.UUID] {
 	v := validatorFactory[uuid.UUID](opts...)
-	v.setTransformer(func(val any) (any, error) {
-		return coerceToUUID(val)
-	})
+	if !v.hasTransformer() {
+		v.setTransformer(func(val any) (any, error) {
+			return coerceToUUID(val)
+		})
+	}
 	return v
 }
 
 func coerceToUUID(val any) (uuid.UUID, error) {
-	vo := reflect.ValueOf(val)
-	if vo.Kind() == reflect.Ptr {
-		vo = vo.Elem()
-		return coerceToUUID(vo.Interface())
-	}
-	if vo.Kind() != reflect.String {
+	vo := reflect.Indirect(reflect.ValueOf(val))
+	if !vo.IsValid() {
 		return uuid.Nil, InvalidValueError
 	}
-	return uuid.Parse(val.(string))
+
+	if n, ok := vo.Interface().(uuid.NullUUID); ok {
+		return n.UUID, nil
+	}
+
+	switch {
+	case vo.Kind() == reflect.String:
+		id, err := uuid.Parse(vo.String())
+		if err != nil {
+			return uuid.Nil, InvalidUUIDError
+		}
+		return id, nil
+	case vo.Kind() == reflect.Array && vo.Type().ConvertibleTo(reflect.TypeOf(uuid.UUID{})):
+		return vo.Convert(reflect.TypeOf(uuid.UUID{})).Interface().(uuid.UUID), nil
+	case vo.Kind() == reflect.Slice && vo.Type().Elem().Kind() == reflect.Uint8:
+		// binary columns hold 16 bytes, anything longer is treated as text
+		id, err := uuid.ParseBytes(vo.Bytes())
+		if len(vo.Bytes()) == 16 {
+			id, err = uuid.FromBytes(vo.Bytes())
+		}
+		if err != nil {
+			return uuid.Nil, InvalidUUIDError
+		}
+		return id, nil
+	}
+	return uuid.Nil, InvalidValueError
 }
 
 func NonNullUUID(message ...string) uuidValidatorOpt {
@@ -57,3 +90,73 @@ func NonNullUUID(message ...string) uuidValidatorOpt {
 		return &parseError{message: "uuid is zero"}
 	}
 }
+
+// UUIDVersion accepts UUIDs of the versions e.g. UUIDVersion(7) to reject random v4 ids
+func UUIDVersion(versions ...uuid.Version) uuidValidatorOpt {
+	allowed := make([]int, len(versions))
+	for i, v := range versions {
+		allowed[i] = int(v)
+	}
+	return func(val *uuid.UUID) *parseError {
+		if val == nil {
+			return nil
+		}
+		for _, v := range versions {
+			if val.Version() == v {
+				return nil
+			}
+		}
+		return &parseError{
+			message: "uuid version not allowed",
+			code:    "invalid_uuid_version",
+			params:  map[string]any{"version": int(val.Version()), "versions": allowed},
+		}
+	}
+}
+
+// UUIDVariant checks the variant bits, use uuid.RFC4122 for the UUIDs generated by the uuid package
+func UUIDVariant(variant uuid.Variant, message ...string) uuidValidatorOpt {
+	return func(val *uuid.UUID) *parseError {
+		if val == nil || val.Variant() == variant {
+			return nil
+		}
+		if len(message) > 0 {
+			return &parseError{message: message[0], code: "invalid_uuid_variant"}
+		}
+		return &parseError{message: "uuid variant not allowed", code: "invalid_uuid_variant"}
+	}
+}
+
+// UUIDNotInFuture rejects time based UUIDs (versions 1, 6 and 7) created later than now plus the allowed clock skew,
+// other versions have no timestamp and are rejected
+func UUIDNotInFuture(skew time.Duration, message ...string) clockedOpt[uuid.UUID] {
+	return func(now time.Time, val uuid.UUID) *parseError {
+		created, ok := uuidTime(val)
+		if ok && !created.After(now.Add(skew)) {
+			return nil
+		}
+		return idTimeError(message...)
+	}
+}
+
+func uuidTime(id uuid.UUID) (time.Time, bool) {
+	switch id.Version() {
+	case 1, 7:
+		sec, nsec := id.Time().UnixTime()
+		return time.Unix(sec, nsec), true
+	case 6:
+		// the 60 bit timestamp is stored most significant bits first with the version between the middle and low bits
+		t := uint64(binary.BigEndian.Uint32(id[0:4]))<<28 | uint64(binary.BigEndian.Uint16(id[4:6]))<<12 |
+			uint64(binary.BigEndian.Uint16(id[6:8])&0xfff)
+		sec, nsec := uuid.Time(t).UnixTime()
+		return time.Unix(sec, nsec), true
+	}
+	return time.Time{}, false
+}
+
+func idTimeError(message ...string) *parseError {
+	if len(message) > 0 {
+		return &parseError{message: message[0], code: "id_in_future"}
+	}
+	return &parseError{message: "id timestamp is in the future", code: "id_in_future"}
+}
@@ -0,0 +1,41 @@
+// Package yaml registers a YAML body decoder with ursa, import it for its side effects:
+//
+//	import _ "github.com/jdudmesh/ursa/yaml"
+package yaml
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	u "github.com/jdudmesh/ursa"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	for _, mediaType := range []string{"application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml"} {
+		u.RegisterDecoder(mediaType, Decode)
+	}
+}
+
+// Decode reads a YAML mapping, the parser stops at a depth of 10000 itself and ursa checks the result against
+// smaller limits
+func Decode(data []byte, maxDepth int) (map[string]interface{}, error) {
+	unpacked := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &unpacked); err != nil {
+		return nil, err
+	}
+	return unpacked, nil
+}
@@ -0,0 +1,43 @@
+package yaml_test
+
+// ursa is a zod inspired validation library for Go.
+// Copyright (C) 2023 John Dudmesh
+
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+import (
+	"testing"
+
+	u "github.com/jdudmesh/ursa"
+	_ "github.com/jdudmesh/ursa/yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode(t *testing.T) {
+	assert := assert.New(t)
+
+	v := u.Object().
+		String("Name", u.MinLength(5)).
+		Int("Count", u.Min(1)).
+		Object("Address", u.Object().String("City", u.Required()))
+
+	res := v.ParseFormat([]byte("Name: abcdef\nCount: 5\nAddress:\n  City: Leeds\n"), "application/yaml")
+	assert.True(res.IsAllValid())
+	assert.Equal("abcdef", res.GetString("Name"))
+	assert.Equal(5, res.GetInt("Count"))
+
+	res = u.Object(u.MaxJSONDepth(2)).ParseFormat([]byte("a:\n  b:\n    c: 1\n"), "application/x-yaml")
+	assert.False(res.IsValid())
+	assert.ErrorIs(res.Errors()[0], u.DocumentTooDeepError)
+}